@@ -12,7 +12,7 @@ func (s *Redis) BitCount(key string, start, end int64) (int64, error) {
 
 // BitCountCtx is redis bitcount command implementation.
 func (s *Redis) BitCountCtx(ctx context.Context, key string, start, end int64) (val int64, err error) {
-	return s.client.BitCount(ctx, key, &red.BitCount{
+	return s.client.BitCount(ctx, s.key(key), &red.BitCount{
 		Start: start,
 		End:   end,
 	}).Result()
@@ -25,7 +25,7 @@ func (s *Redis) BitOpAnd(destKey string, keys ...string) (int64, error) {
 
 // BitOpAndCtx is redis bit operation (and) command implementation.
 func (s *Redis) BitOpAndCtx(ctx context.Context, destKey string, keys ...string) (val int64, err error) {
-	return s.client.BitOpAnd(ctx, destKey, keys...).Result()
+	return s.client.BitOpAnd(ctx, s.key(destKey), s.keys(keys)...).Result()
 }
 
 // BitOpNot is redis bit operation (not) command implementation.
@@ -35,7 +35,7 @@ func (s *Redis) BitOpNot(destKey, key string) (int64, error) {
 
 // BitOpNotCtx is redis bit operation (not) command implementation.
 func (s *Redis) BitOpNotCtx(ctx context.Context, destKey, key string) (val int64, err error) {
-	return s.client.BitOpNot(ctx, destKey, key).Result()
+	return s.client.BitOpNot(ctx, s.key(destKey), s.key(key)).Result()
 }
 
 // BitOpOr is redis bit operation (or) command implementation.
@@ -45,7 +45,7 @@ func (s *Redis) BitOpOr(destKey string, keys ...string) (int64, error) {
 
 // BitOpOrCtx is redis bit operation (or) command implementation.
 func (s *Redis) BitOpOrCtx(ctx context.Context, destKey string, keys ...string) (val int64, err error) {
-	return s.client.BitOpOr(ctx, destKey, keys...).Result()
+	return s.client.BitOpOr(ctx, s.key(destKey), s.keys(keys)...).Result()
 }
 
 // BitOpXor is redis bit operation (xor) command implementation.
@@ -55,7 +55,7 @@ func (s *Redis) BitOpXor(destKey string, keys ...string) (int64, error) {
 
 // BitOpXorCtx is redis bit operation (xor) command implementation.
 func (s *Redis) BitOpXorCtx(ctx context.Context, destKey string, keys ...string) (val int64, err error) {
-	return s.client.BitOpXor(ctx, destKey, keys...).Result()
+	return s.client.BitOpXor(ctx, s.key(destKey), s.keys(keys)...).Result()
 }
 
 // BitPos is redis bitpos command implementation.
@@ -65,5 +65,5 @@ func (s *Redis) BitPos(key string, bit, start, end int64) (int64, error) {
 
 // BitPosCtx is redis bitpos command implementation.
 func (s *Redis) BitPosCtx(ctx context.Context, key string, bit, start, end int64) (val int64, err error) {
-	return s.client.BitPos(ctx, key, bit, start, end).Result()
+	return s.client.BitPos(ctx, s.key(key), bit, start, end).Result()
 }
@@ -8,25 +8,294 @@
 
 package rredis
 
-import "math/rand"
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"time"
+)
 
-func (s *Redis) GetWithCache(key string, minExpire, maxExpire int64, queryFunc func() (interface{}, error)) (interface{}, error) {
-	result, err := s.Get(key)
-	if err == nil {
-		return result, nil
+const (
+	rebuildLockSuffix  = ":rebuild"
+	rebuildPollPeriod  = 50 * time.Millisecond
+	defaultCacheExpire = time.Hour
+	// defaultCacheJitter spreads the expirations of the values cached together by GetManyWithCache.
+	defaultCacheJitter = 6 * time.Minute
+
+	// notFoundPlaceholder is the tombstone of keys not found by GetWithCache loaders,
+	// its NUL bytes keep it apart from the text values, which are stored as is.
+	notFoundPlaceholder   = "\x00rredis:not-found\x00"
+	defaultNotFoundExpire = time.Minute
+
+	defaultInvalidationChannel = "rredis:cache:invalidation"
+
+	// releaseScript deletes the lock only if it's still held by the given token.
+	releaseScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`
+)
+
+type (
+	// CacheOption customizes the cache helpers like GetWithCache.
+	CacheOption func(*cacheOptions)
+
+	cacheOptions struct {
+		codec      Codec
+		minExpire  time.Duration
+		maxExpire  time.Duration
+		lockExpire time.Duration
+		lockWait   time.Duration
+		// notFoundExpire is the expiration of tombstones, negative disables them.
+		notFoundExpire time.Duration
+		localSize      int
+		localExpire    time.Duration
+		channel        string
+		// beta scales the early refreshes of XFetch, grace is how long stale values are served.
+		beta  float64
+		grace time.Duration
+		// raw stores values without flag, like GetWithCache, see GetManyWithCache.
+		raw bool
 	}
+)
 
-	data, err := queryFunc()
+// WithCodec sets the codec of cached values, JSONCodec by default.
+// It's used by Cache, GetWithCache stores the values as is.
+func WithCodec(codec Codec) CacheOption {
+	return func(o *cacheOptions) {
+		o.codec = codec
+	}
+}
+
+// WithCacheExpire caches values for a random duration between minExpire and maxExpire,
+// one hour by default. It's used by Cache, GetWithCache takes the expiration as arguments.
+func WithCacheExpire(minExpire, maxExpire time.Duration) CacheOption {
+	return func(o *cacheOptions) {
+		o.minExpire = minExpire
+		o.maxExpire = maxExpire
+	}
+}
+
+// WithNotFoundExpire caches the loaders' ErrNotFound as a tombstone for expire, one minute by default.
+// Until the tombstone expires, lookups of the key return ErrNotFound without calling the loader.
+// A negative expire disables the tombstones.
+func WithNotFoundExpire(expire time.Duration) CacheOption {
+	return func(o *cacheOptions) {
+		o.notFoundExpire = expire
+	}
+}
+
+// WithLocalCache keeps up to size values of Cache in memory for expire, in front of redis.
+// Set and Delete broadcast invalidations over redis pub/sub, so that all instances drop their copies.
+func WithLocalCache(size int, expire time.Duration) CacheOption {
+	return func(o *cacheOptions) {
+		o.localSize = size
+		o.localExpire = expire
+	}
+}
+
+// WithInvalidationChannel sets the pub/sub channel of local cache invalidations,
+// caches sharing a channel invalidate each other.
+func WithInvalidationChannel(channel string) CacheOption {
+	return func(o *cacheOptions) {
+		o.channel = channel
+	}
+}
+
+// WithEarlyRefresh makes Cache refresh values in the background before they expire,
+// with a probability growing as the expiration nears and with the time taken to load them (XFetch).
+// beta scales the earliness, 1 is the usual choice, greater values refresh earlier.
+func WithEarlyRefresh(beta float64) CacheOption {
+	return func(o *cacheOptions) {
+		o.beta = beta
+	}
+}
+
+// WithStaleWhileRevalidate makes Cache keep expired values for grace, serving them
+// while one background refresh runs. If the refresh fails, the stale value is served
+// until the grace window ends.
+func WithStaleWhileRevalidate(grace time.Duration) CacheOption {
+	return func(o *cacheOptions) {
+		o.grace = grace
+	}
+}
+
+// WithRebuildLock makes only one instance rebuild a missing key, guarded by a redis lock
+// that expires after expire. Other instances poll the cache for up to wait,
+// then load the value themselves.
+func WithRebuildLock(expire, wait time.Duration) CacheOption {
+	return func(o *cacheOptions) {
+		o.lockExpire = expire
+		o.lockWait = wait
+	}
+}
+
+func newCacheOptions(opts ...CacheOption) *cacheOptions {
+	o := &cacheOptions{
+		codec:          JSONCodec{},
+		minExpire:      defaultCacheExpire,
+		maxExpire:      defaultCacheExpire,
+		notFoundExpire: defaultNotFoundExpire,
+		channel:        defaultInvalidationChannel,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// GetWithCache returns the cached value of key, or loads it with queryFunc and caches it
+// for a random duration between minExpire and maxExpire seconds.
+// Concurrent misses of the same key in one process share one queryFunc call.
+// If queryFunc returns ErrNotFound, it's cached as a tombstone, see WithNotFoundExpire.
+func (s *Redis) GetWithCache(key string, minExpire, maxExpire int64, queryFunc func() (interface{}, error),
+	opts ...CacheOption) (interface{}, error) {
+	return s.GetWithCacheCtx(s.ctx, key, minExpire, maxExpire, queryFunc, opts...)
+}
+
+// GetWithCacheCtx returns the cached value of key, or loads it with queryFunc and caches it
+// for a random duration between minExpire and maxExpire seconds.
+// Concurrent misses of the same key in one process share one queryFunc call.
+// If queryFunc returns ErrNotFound, it's cached as a tombstone, see WithNotFoundExpire.
+func (s *Redis) GetWithCacheCtx(ctx context.Context, key string, minExpire, maxExpire int64,
+	queryFunc func() (interface{}, error), opts ...CacheOption) (interface{}, error) {
+	result, err := s.getCached(ctx, key)
+	if err == nil || err == ErrNotFound {
+		s.stats.hit(s.prefix, 1)
+		return result, err
+	}
+	s.stats.miss(s.prefix, 1)
+
+	o := newCacheOptions(opts...)
+	return s.flight.Do(s.key(key), func() (interface{}, error) {
+		if o.lockExpire > 0 {
+			return s.rebuildWithLock(ctx, key, o, func() (interface{}, error) {
+				return s.getCached(ctx, key)
+			}, func() (interface{}, error) {
+				return s.loadAndCache(ctx, key, minExpire, maxExpire, o, queryFunc)
+			})
+		}
+
+		return s.loadAndCache(ctx, key, minExpire, maxExpire, o, queryFunc)
+	})
+}
+
+// GetManyWithCache returns the cached values of keys, the missing ones are loaded with one loader call
+// and cached in one pipeline, each for a random duration set with WithCacheExpire,
+// between one hour and one hour and six minutes by default.
+// Values are stored like GetWithCache stores them, so that both share keys: strings, numbers, booleans
+// and encoding.BinaryMarshaler as is, other values with JSON, or with the codec set with WithCodec.
+// Values that can't be decoded are loaded again. Keys that are neither cached nor loaded are absent
+// from the result, and cached as not found. WithEarlyRefresh and WithStaleWhileRevalidate don't apply.
+func GetManyWithCache[T any](rds *Redis, keys []string, loader func(missing []string) (map[string]T, error),
+	opts ...CacheOption) (map[string]T, error) {
+	return GetManyWithCacheCtx(rds.ctx, rds, keys, loader, opts...)
+}
+
+// GetManyWithCacheCtx returns the cached values of keys, the missing ones are loaded with one loader call
+// and cached in one pipeline, see GetManyWithCache.
+func GetManyWithCacheCtx[T any](ctx context.Context, rds *Redis, keys []string,
+	loader func(missing []string) (map[string]T, error), opts ...CacheOption) (map[string]T, error) {
+	defaults := []CacheOption{
+		WithCodec(textCodec{}),
+		WithCacheExpire(defaultCacheExpire, defaultCacheExpire+defaultCacheJitter),
+	}
+	o := newCacheOptions(append(defaults, opts...)...)
+	o.raw = true
+	o.beta, o.grace = 0, 0
+	c := &Cache[T]{
+		rds:  rds,
+		opts: o,
+	}
+
+	return c.MGetOrLoad(ctx, keys, func(ctx context.Context, missing []string) (map[string]T, error) {
+		return loader(missing)
+	})
+}
+
+// getCached returns the cached value of key, or ErrNotFound if it's a tombstone.
+func (s *Redis) getCached(ctx context.Context, key string) (interface{}, error) {
+	result, err := s.client.Get(ctx, s.key(key)).Result()
 	if err != nil {
 		return nil, err
 	}
+	if result == notFoundPlaceholder {
+		return nil, ErrNotFound
+	}
 
-	expire := rand.Int63n(maxExpire-minExpire+1) + minExpire
+	return result, nil
+}
 
-	err = s.SetEx(key, data, expire)
+func (s *Redis) loadAndCache(ctx context.Context, key string, minExpire, maxExpire int64, o *cacheOptions,
+	queryFunc func() (interface{}, error)) (interface{}, error) {
+	start := time.Now()
+	data, err := queryFunc()
+	s.stats.load(s.prefix, time.Since(start), err)
+	if errors.Is(err, ErrNotFound) && o.notFoundExpire > 0 {
+		if err := s.client.Set(ctx, s.key(key), notFoundPlaceholder, o.notFoundExpire).Err(); err != nil {
+			return nil, err
+		}
+		return nil, ErrNotFound
+	}
 	if err != nil {
 		return nil, err
 	}
 
+	if err = s.SetExCtx(ctx, key, data, randomExpire(minExpire, maxExpire)); err != nil {
+		return nil, err
+	}
+
 	return data, nil
 }
+
+// rebuildWithLock calls rebuild if it gets the rebuild lock of key and key is still missing,
+// otherwise it waits for the lock holder to cache the value, polling it with read.
+func (s *Redis) rebuildWithLock(ctx context.Context, key string, o *cacheOptions,
+	read, rebuild func() (interface{}, error)) (interface{}, error) {
+	lockKey := key + rebuildLockSuffix
+	token := randomToken()
+	ok, err := s.client.SetNX(ctx, s.key(lockKey), token, o.lockExpire).Result()
+	if err == nil && ok {
+		defer s.EvalCtx(context.Background(), releaseScript, []string{lockKey}, token)
+		// the previous holder may have cached the value between our miss and the lock.
+		if result, err := read(); err == nil || err == ErrNotFound {
+			return result, err
+		}
+		return rebuild()
+	}
+
+	deadline := time.Now().Add(o.lockWait)
+	for time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(rebuildPollPeriod):
+		}
+
+		if result, err := read(); err == nil || err == ErrNotFound {
+			return result, err
+		}
+	}
+
+	return rebuild()
+}
+
+// expire returns a random expiration between minExpire and maxExpire.
+func (o *cacheOptions) expire() time.Duration {
+	if o.maxExpire <= o.minExpire {
+		return o.minExpire
+	}
+
+	return o.minExpire + time.Duration(rand.Int63n(int64(o.maxExpire-o.minExpire)+1))
+}
+
+// randomExpire returns a random expiration between minExpire and maxExpire,
+// so that keys cached together don't expire together.
+func randomExpire(minExpire, maxExpire int64) int64 {
+	if maxExpire <= minExpire {
+		return minExpire
+	}
+
+	return rand.Int63n(maxExpire-minExpire+1) + minExpire
+}
@@ -0,0 +1,416 @@
+package rredis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	red "github.com/redis/go-redis/v9"
+)
+
+// memoryHook answers the string and hash commands and the lock and tag scripts used by the helpers
+// from memory, without a server. Expirations and published messages are recorded, but keys don't expire.
+type memoryHook struct {
+	lock      sync.Mutex
+	data      map[string]string
+	hashes    map[string]map[string]string
+	zsets     map[string]map[string]float64
+	expires   map[string]time.Duration
+	published []string
+	// before is called with each command before it's answered, outside of the lock.
+	before func(cmd red.Cmder)
+}
+
+// newMemoryRedis returns a Redis served by a memoryHook.
+func newMemoryRedis() (*Redis, *memoryHook) {
+	h := &memoryHook{
+		data:    make(map[string]string),
+		hashes:  make(map[string]map[string]string),
+		zsets:   make(map[string]map[string]float64),
+		expires: make(map[string]time.Duration),
+	}
+	c := red.NewClient(&red.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+	c.AddHook(h)
+	return FromClient(c), h
+}
+
+func (h *memoryHook) DialHook(next red.DialHook) red.DialHook {
+	return next
+}
+
+func (h *memoryHook) ProcessHook(next red.ProcessHook) red.ProcessHook {
+	return func(ctx context.Context, cmd red.Cmder) error {
+		h.process(cmd)
+		return cmd.Err()
+	}
+}
+
+func (h *memoryHook) ProcessPipelineHook(next red.ProcessPipelineHook) red.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []red.Cmder) error {
+		for _, cmd := range cmds {
+			h.process(cmd)
+		}
+		return nil
+	}
+}
+
+func (h *memoryHook) process(cmd red.Cmder) {
+	if h.before != nil {
+		h.before(cmd)
+	}
+
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	args := cmd.Args()
+	switch cmd.Name() {
+	case "get":
+		val, ok := h.data[memoryString(args[1])]
+		if !ok {
+			cmd.SetErr(red.Nil)
+			return
+		}
+		cmd.(*red.StringCmd).SetVal(val)
+	case "mget":
+		vals := make([]interface{}, len(args)-1)
+		for i, key := range args[1:] {
+			if val, ok := h.data[memoryString(key)]; ok {
+				vals[i] = val
+			}
+		}
+		cmd.(*red.SliceCmd).SetVal(vals)
+	case "set", "setnx":
+		key := memoryString(args[1])
+		nx := cmd.Name() == "setnx" || strings.EqualFold(memoryString(args[len(args)-1]), "nx")
+		if _, ok := h.data[key]; ok && nx {
+			cmd.(*red.BoolCmd).SetVal(false)
+			return
+		}
+		h.data[key] = memoryString(args[2])
+		delete(h.expires, key)
+		for i := 3; i+1 < len(args); i++ {
+			n, _ := strconv.ParseInt(memoryString(args[i+1]), 10, 64)
+			switch strings.ToLower(memoryString(args[i])) {
+			case "ex":
+				h.expires[key] = time.Duration(n) * time.Second
+			case "px":
+				h.expires[key] = time.Duration(n) * time.Millisecond
+			}
+		}
+		switch c := cmd.(type) {
+		case *red.BoolCmd:
+			c.SetVal(true)
+		case *red.StatusCmd:
+			c.SetVal("OK")
+		}
+	case "hset":
+		hash := h.hashes[memoryString(args[1])]
+		if hash == nil {
+			hash = make(map[string]string)
+			h.hashes[memoryString(args[1])] = hash
+		}
+		for i := 2; i+1 < len(args); i += 2 {
+			hash[memoryString(args[i])] = memoryString(args[i+1])
+		}
+		cmd.(*red.IntCmd).SetVal(int64(len(args)-2) / 2)
+	case "hdel":
+		var n int64
+		for _, field := range args[2:] {
+			if _, ok := h.hashes[memoryString(args[1])][memoryString(field)]; ok {
+				delete(h.hashes[memoryString(args[1])], memoryString(field))
+				n++
+			}
+		}
+		cmd.(*red.IntCmd).SetVal(n)
+	case "hgetall":
+		hash := make(map[string]string)
+		for field, val := range h.hashes[memoryString(args[1])] {
+			hash[field] = val
+		}
+		cmd.(*red.MapStringStringCmd).SetVal(hash)
+	case "multi":
+		cmd.(*red.StatusCmd).SetVal("OK")
+	case "exec":
+		cmd.(*red.SliceCmd).SetVal(nil)
+	case "del":
+		var n int64
+		for _, key := range args[1:] {
+			if _, ok := h.data[memoryString(key)]; ok {
+				delete(h.data, memoryString(key))
+				n++
+			}
+		}
+		cmd.(*red.IntCmd).SetVal(n)
+	case "publish":
+		h.published = append(h.published, memoryString(args[2]))
+		cmd.(*red.IntCmd).SetVal(0)
+	case "eval":
+		h.eval(cmd.(*red.Cmd), args)
+	default:
+		cmd.SetErr(fmt.Errorf("unsupported command %q", cmd.Name()))
+	}
+}
+
+// eval runs the scripts releasing locks and tagging keys, the others return 0.
+func (h *memoryHook) eval(cmd *red.Cmd, args []interface{}) {
+	numKeys, _ := strconv.Atoi(memoryString(args[2]))
+	keys := make([]string, numKeys)
+	for i := range keys {
+		keys[i] = memoryString(args[3+i])
+	}
+	argv := args[3+numKeys:]
+
+	switch args[1] {
+	case releaseScript:
+		if h.data[keys[0]] != memoryString(argv[0]) {
+			cmd.SetVal(int64(0))
+			return
+		}
+		delete(h.data, keys[0])
+		cmd.SetVal(int64(1))
+	case tagScript:
+		zset := h.zsets[keys[0]]
+		if zset == nil {
+			zset = make(map[string]float64)
+			h.zsets[keys[0]] = zset
+		}
+		score, _ := strconv.ParseFloat(memoryString(argv[1]), 64)
+		now, _ := strconv.ParseFloat(memoryString(argv[2]), 64)
+		zset[memoryString(argv[0])] = score
+		var last float64
+		for member, score := range zset {
+			if score <= now {
+				delete(zset, member)
+			} else if score > last {
+				last = score
+			}
+		}
+		delete(h.expires, keys[0])
+		if len(zset) > 0 && last < math.Inf(1) {
+			h.expires[keys[0]] = time.Duration(last-now) * time.Millisecond
+		}
+		cmd.SetVal(int64(1))
+	case invalidateTagsScript:
+		var deleted int64
+		for _, key := range keys {
+			for _, member := range h.zrange(key) {
+				if _, ok := h.data[member]; ok {
+					delete(h.data, member)
+					deleted++
+				}
+			}
+			delete(h.zsets, key)
+		}
+		cmd.SetVal(deleted)
+	case popTagScript:
+		members := make([]interface{}, 0, len(h.zsets[keys[0]]))
+		for _, member := range h.zrange(keys[0]) {
+			members = append(members, member)
+		}
+		delete(h.zsets, keys[0])
+		cmd.SetVal(members)
+	default:
+		cmd.SetVal(int64(0))
+	}
+}
+
+// zrange returns the members of the sorted set key by score.
+func (h *memoryHook) zrange(key string) []string {
+	zset := h.zsets[key]
+	members := make([]string, 0, len(zset))
+	for member := range zset {
+		members = append(members, member)
+	}
+	sort.Slice(members, func(i, j int) bool {
+		if zset[members[i]] != zset[members[j]] {
+			return zset[members[i]] < zset[members[j]]
+		}
+		return members[i] < members[j]
+	})
+
+	return members
+}
+
+func memoryString(arg interface{}) string {
+	switch v := arg.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func TestGetWithCacheNotFound(t *testing.T) {
+	rds, _ := newMemoryRedis()
+
+	var loads int
+	load := func() (interface{}, error) {
+		loads++
+		return nil, fmt.Errorf("user 1: %w", ErrNotFound)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := rds.GetWithCache("user:1", 60, 60, load); err != ErrNotFound {
+			t.Fatalf("expected ErrNotFound, got %v", err)
+		}
+	}
+	if loads != 1 {
+		t.Fatalf("expected the tombstone to save the second load, got %d loads", loads)
+	}
+
+	if _, err := rds.GetWithCache("user:2", 60, 60, load, WithNotFoundExpire(-1)); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if _, ok, err := rds.GetOk("user:2"); ok || err != nil {
+		t.Fatalf("expected no tombstone when they're disabled, got %v, %v", ok, err)
+	}
+}
+
+func TestGetWithCachePlaceholderValue(t *testing.T) {
+	rds, _ := newMemoryRedis()
+
+	// values looking like the former tombstone are values.
+	val, err := rds.GetWithCache("star", 60, 60, func() (interface{}, error) {
+		return "*", nil
+	})
+	if err != nil || val != "*" {
+		t.Fatalf("expected the loaded value, got %v, %v", val, err)
+	}
+
+	val, err = rds.GetWithCache("star", 60, 60, func() (interface{}, error) {
+		t.Fatal("expected the cached value")
+		return nil, nil
+	})
+	if err != nil || val != "*" {
+		t.Fatalf("expected the cached value, got %v, %v", val, err)
+	}
+}
+
+func TestRebuildWithLock(t *testing.T) {
+	rds, h := newMemoryRedis()
+	o := newCacheOptions(WithRebuildLock(time.Second, time.Second))
+
+	var rebuilds int
+	rebuild := func() (interface{}, error) {
+		rebuilds++
+		return "built", nil
+	}
+	read := func() (interface{}, error) {
+		return rds.getCached(context.Background(), "user:1")
+	}
+
+	// the value cached by the previous lock holder is read after taking the lock.
+	h.data["user:1"] = "cached"
+	val, err := rds.rebuildWithLock(context.Background(), "user:1", o, read, rebuild)
+	if err != nil || val != "cached" || rebuilds != 0 {
+		t.Fatalf("expected the cached value without rebuild, got %v, %v, %d rebuilds", val, err, rebuilds)
+	}
+
+	delete(h.data, "user:1")
+	val, err = rds.rebuildWithLock(context.Background(), "user:1", o, read, rebuild)
+	if err != nil || val != "built" || rebuilds != 1 {
+		t.Fatalf("expected the value rebuilt, got %v, %v, %d rebuilds", val, err, rebuilds)
+	}
+	if _, ok := h.data["user:1"+rebuildLockSuffix]; ok {
+		t.Fatal("expected the rebuild lock released")
+	}
+}
+
+func TestGetManyWithCache(t *testing.T) {
+	rds, h := newMemoryRedis()
+
+	var loaded [][]string
+	loader := func(missing []string) (map[string]string, error) {
+		loaded = append(loaded, missing)
+		return map[string]string{"user:1": "ann"}, nil
+	}
+
+	vals, err := GetManyWithCache(rds, []string{"user:1", "user:2"}, loader)
+	if err != nil || len(vals) != 1 || vals["user:1"] != "ann" {
+		t.Fatalf("expected the loaded value, got %v, %v", vals, err)
+	}
+	if h.data["user:1"] != "ann" || h.data["user:2"] != notFoundPlaceholder {
+		t.Fatalf("expected the value and the tombstone cached like GetWithCache, got %q", h.data)
+	}
+
+	// hits and tombstones don't call the loader.
+	vals, err = GetManyWithCache(rds, []string{"user:1", "user:2"}, loader)
+	if err != nil || len(vals) != 1 || vals["user:1"] != "ann" || len(loaded) != 1 {
+		t.Fatalf("expected the cached values, got %v, %v, loads %v", vals, err, loaded)
+	}
+
+	val, err := rds.GetWithCache("user:1", 60, 60, func() (interface{}, error) {
+		t.Fatal("expected the value cached by GetManyWithCache")
+		return nil, nil
+	})
+	if err != nil || val != "ann" {
+		t.Fatalf("expected the value cached by GetManyWithCache, got %v, %v", val, err)
+	}
+	if _, err = rds.GetWithCache("user:2", 60, 60, nil); err != ErrNotFound {
+		t.Fatalf("expected the tombstone cached by GetManyWithCache, got %v", err)
+	}
+}
+
+func TestGetManyWithCacheShared(t *testing.T) {
+	rds, h := newMemoryRedis()
+
+	if _, err := rds.GetWithCache("count:1", 60, 60, func() (interface{}, error) {
+		return 42, nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+	// a value that can't be decoded is loaded again.
+	h.data["count:2"] = "forty-two"
+
+	vals, err := GetManyWithCache(rds, []string{"count:1", "count:2"}, func(missing []string) (map[string]int, error) {
+		if len(missing) != 1 || missing[0] != "count:2" {
+			t.Fatalf("expected only the undecodable key loaded, got %v", missing)
+		}
+		return map[string]int{"count:2": 7}, nil
+	})
+	if err != nil || vals["count:1"] != 42 || vals["count:2"] != 7 {
+		t.Fatalf("expected the values shared with GetWithCache, got %v, %v", vals, err)
+	}
+	if h.data["count:2"] != "7" {
+		t.Fatalf("expected the reloaded value cached as text, got %q", h.data["count:2"])
+	}
+}
+
+func TestGetManyWithCacheJitter(t *testing.T) {
+	rds, h := newMemoryRedis()
+
+	keys := make([]string, 10)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("user:%d", i)
+	}
+	_, err := GetManyWithCache(rds, keys, func(missing []string) (map[string]string, error) {
+		loaded := make(map[string]string, len(missing))
+		for _, key := range missing {
+			loaded[key] = key
+		}
+		return loaded, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := make(map[time.Duration]bool)
+	for _, key := range keys {
+		expire := h.expires[key]
+		if expire < defaultCacheExpire || expire > defaultCacheExpire+defaultCacheJitter {
+			t.Fatalf("unexpected expiration %s of %s", expire, key)
+		}
+		seen[expire] = true
+	}
+	if len(seen) < 2 {
+		t.Fatal("expected jittered expirations by default")
+	}
+}
@@ -0,0 +1,191 @@
+package rredis
+
+import (
+	"bytes"
+	"context"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	red "github.com/redis/go-redis/v9"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type (
+	// Codec encodes values to bytes stored in redis, and decodes them back.
+	Codec interface {
+		Marshal(v interface{}) ([]byte, error)
+		Unmarshal(data []byte, v interface{}) error
+	}
+
+	// JSONCodec is a Codec using encoding/json.
+	JSONCodec struct{}
+
+	// GobCodec is a Codec using encoding/gob.
+	GobCodec struct{}
+
+	// MsgpackCodec is a Codec using msgpack.
+	MsgpackCodec struct{}
+)
+
+// Marshal implements Codec.
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal implements Codec.
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// Marshal implements Codec.
+func (GobCodec) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Unmarshal implements Codec.
+func (GobCodec) Unmarshal(data []byte, v interface{}) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(v)
+}
+
+// Marshal implements Codec.
+func (MsgpackCodec) Marshal(v interface{}) ([]byte, error) {
+	return msgpack.Marshal(v)
+}
+
+// Unmarshal implements Codec.
+func (MsgpackCodec) Unmarshal(data []byte, v interface{}) error {
+	return msgpack.Unmarshal(data, v)
+}
+
+// SetWithCodec encodes value with codec and sets it to key for seconds, 0 means it doesn't expire.
+func (s *Redis) SetWithCodec(key string, value interface{}, seconds int64, codec Codec) error {
+	return s.SetWithCodecCtx(s.ctx, key, value, seconds, codec)
+}
+
+// SetWithCodecCtx encodes value with codec and sets it to key for seconds, 0 means it doesn't expire.
+func (s *Redis) SetWithCodecCtx(ctx context.Context, key string, value interface{}, seconds int64,
+	codec Codec) error {
+	data, err := codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return s.client.Set(ctx, s.key(key), data, time.Duration(seconds)*time.Second).Err()
+}
+
+// GetWithCodec gets key and decodes it into v with codec, it returns Nil if key doesn't exist.
+func (s *Redis) GetWithCodec(key string, v interface{}, codec Codec) error {
+	return s.GetWithCodecCtx(s.ctx, key, v, codec)
+}
+
+// GetWithCodecCtx gets key and decodes it into v with codec, it returns Nil if key doesn't exist.
+func (s *Redis) GetWithCodecCtx(ctx context.Context, key string, v interface{}, codec Codec) error {
+	data, err := s.client.Get(ctx, s.key(key)).Bytes()
+	if err != nil {
+		return err
+	}
+
+	return codec.Unmarshal(data, v)
+}
+
+// HSetWithCodec encodes value with codec and sets it to field of key.
+func (s *Redis) HSetWithCodec(key, field string, value interface{}, codec Codec) error {
+	return s.HSetWithCodecCtx(s.ctx, key, field, value, codec)
+}
+
+// HSetWithCodecCtx encodes value with codec and sets it to field of key.
+func (s *Redis) HSetWithCodecCtx(ctx context.Context, key, field string, value interface{}, codec Codec) error {
+	data, err := codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return s.client.HSet(ctx, s.key(key), field, data).Err()
+}
+
+// HGetWithCodec gets field of key and decodes it into v with codec, it returns Nil if field doesn't exist.
+func (s *Redis) HGetWithCodec(key, field string, v interface{}, codec Codec) error {
+	return s.HGetWithCodecCtx(s.ctx, key, field, v, codec)
+}
+
+// HGetWithCodecCtx gets field of key and decodes it into v with codec, it returns Nil if field doesn't exist.
+func (s *Redis) HGetWithCodecCtx(ctx context.Context, key, field string, v interface{}, codec Codec) error {
+	data, err := s.client.HGet(ctx, s.key(key), field).Bytes()
+	if err != nil {
+		return err
+	}
+
+	return codec.Unmarshal(data, v)
+}
+
+// textCodec stores values as GetWithCache does: strings, numbers, booleans, time.Time, time.Duration
+// and encoding.BinaryMarshaler are formatted like redis command arguments, other values with JSON.
+type textCodec struct{}
+
+// Marshal implements Codec.
+func (textCodec) Marshal(v interface{}) ([]byte, error) {
+	switch v := v.(type) {
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	case int:
+		return strconv.AppendInt(nil, int64(v), 10), nil
+	case int8:
+		return strconv.AppendInt(nil, int64(v), 10), nil
+	case int16:
+		return strconv.AppendInt(nil, int64(v), 10), nil
+	case int32:
+		return strconv.AppendInt(nil, int64(v), 10), nil
+	case int64:
+		return strconv.AppendInt(nil, v, 10), nil
+	case uint:
+		return strconv.AppendUint(nil, uint64(v), 10), nil
+	case uint8:
+		return strconv.AppendUint(nil, uint64(v), 10), nil
+	case uint16:
+		return strconv.AppendUint(nil, uint64(v), 10), nil
+	case uint32:
+		return strconv.AppendUint(nil, uint64(v), 10), nil
+	case uint64:
+		return strconv.AppendUint(nil, v, 10), nil
+	case float32:
+		return strconv.AppendFloat(nil, float64(v), 'f', -1, 64), nil
+	case float64:
+		return strconv.AppendFloat(nil, v, 'f', -1, 64), nil
+	case bool:
+		if v {
+			return []byte("1"), nil
+		}
+		return []byte("0"), nil
+	case time.Time:
+		return v.AppendFormat(nil, time.RFC3339Nano), nil
+	case time.Duration:
+		return strconv.AppendInt(nil, v.Nanoseconds(), 10), nil
+	case encoding.BinaryMarshaler:
+		return v.MarshalBinary()
+	}
+
+	return json.Marshal(v)
+}
+
+// Unmarshal implements Codec.
+func (textCodec) Unmarshal(data []byte, v interface{}) error {
+	switch v.(type) {
+	case *string, *[]byte, *int, *int8, *int16, *int32, *int64, *uint, *uint8, *uint16, *uint32, *uint64,
+		*float32, *float64, *bool, *time.Time, *time.Duration, encoding.BinaryUnmarshaler:
+		cmd := red.NewStringCmd(context.Background())
+		cmd.SetVal(string(data))
+		return cmd.Scan(v)
+	}
+
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,125 @@
+package rredis
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type codecUser struct {
+	Name     string
+	Age      int
+	Tags     []string
+	Birthday time.Time
+}
+
+func TestCodecs(t *testing.T) {
+	user := codecUser{
+		Name:     "tom",
+		Age:      18,
+		Tags:     []string{"a", "b"},
+		Birthday: time.Date(2000, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+
+	for name, codec := range map[string]Codec{
+		"json":    JSONCodec{},
+		"gob":     GobCodec{},
+		"msgpack": MsgpackCodec{},
+	} {
+		data, err := codec.Marshal(user)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+
+		var got codecUser
+		if err = codec.Unmarshal(data, &got); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		// msgpack decodes time in the local location
+		got.Birthday = got.Birthday.UTC()
+		if !reflect.DeepEqual(got, user) {
+			t.Errorf("%s: expected %+v, got %+v", name, user, got)
+		}
+	}
+}
+
+func TestCompressCodec(t *testing.T) {
+	small := []string{"a"}
+	large := make([]string, 200)
+	for i := range large {
+		large[i] = "repeated value"
+	}
+
+	for name, compression := range map[string]Compression{"gzip": Gzip, "zstd": Zstd} {
+		codec := NewCompressCodec(JSONCodec{}, compression, 64)
+		for _, val := range [][]string{small, large} {
+			data, err := codec.Marshal(val)
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if compressed := data[0] == compressedFlag; compressed != (len(val) == len(large)) {
+				t.Fatalf("%s: unexpected compression of %d values: %v", name, len(val), compressed)
+			}
+
+			var got []string
+			if err = codec.Unmarshal(data, &got); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if !reflect.DeepEqual(got, val) {
+				t.Fatalf("%s: expected %v, got %v", name, val, got)
+			}
+		}
+	}
+
+	// values written by another algorithm, or before compression, still decode.
+	data, _ := NewCompressCodec(JSONCodec{}, Gzip, 0).Marshal(large)
+	var got []string
+	if err := NewCompressCodec(JSONCodec{}, Zstd, 0).Unmarshal(data, &got); err != nil || len(got) != len(large) {
+		t.Fatalf("expected gzip value to decode, got %v", err)
+	}
+	if err := NewCompressCodec(JSONCodec{}, Zstd, 0).Unmarshal([]byte(`["a"]`), &got); err != nil {
+		t.Fatalf("expected plain value to decode, got %v", err)
+	}
+}
+
+func TestEncryptCodec(t *testing.T) {
+	oldKey := []byte("0123456789abcdef")
+	newKey := []byte("0123456789abcdef0123456789abcdef")
+
+	old, err := NewEncryptCodec(JSONCodec{}, "v1", map[string][]byte{"v1": oldKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := NewEncryptCodec(JSONCodec{}, "v2", map[string][]byte{"v1": oldKey, "v2": newKey})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := old.Marshal("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("secret")) {
+		t.Fatal("expected the value to be encrypted")
+	}
+
+	var got string
+	if err = rotated.Unmarshal(data, &got); err != nil || got != "secret" {
+		t.Fatalf("expected the old value to decrypt, got %q, %v", got, err)
+	}
+
+	data, _ = rotated.Marshal("secret")
+	if err = old.Unmarshal(data, &got); err != ErrUnknownKey {
+		t.Fatalf("expected ErrUnknownKey, got %v", err)
+	}
+
+	data[len(data)-1] ^= 1
+	if err = rotated.Unmarshal(data, &got); err != ErrInvalidCiphertext {
+		t.Fatalf("expected ErrInvalidCiphertext, got %v", err)
+	}
+
+	if _, err = NewEncryptCodec(JSONCodec{}, "v1", map[string][]byte{"v1": []byte("short")}); err == nil {
+		t.Fatal("expected an error with an invalid key size")
+	}
+}
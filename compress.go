@@ -0,0 +1,130 @@
+package rredis
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// Gzip compresses values with gzip.
+	Gzip Compression = iota + 1
+	// Zstd compresses values with zstd.
+	Zstd
+
+	// DefaultCompressThreshold is the size in bytes above which CompressCodec compresses by default.
+	DefaultCompressThreshold = 1024
+
+	// compressedFlag prefixes compressed values, it's never the first byte of json, msgpack or gob,
+	// so values written before compression was enabled still decode.
+	// The algorithm is told by the magic number of the compressed stream.
+	compressedFlag byte = 0xc1
+)
+
+var (
+	// ErrUnknownCompression is an error that indicates a compressed value of an unknown algorithm.
+	ErrUnknownCompression = errors.New("unknown compression")
+
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+	zstdOnce    sync.Once
+	zstdEncoder *zstd.Encoder
+	zstdDecoder *zstd.Decoder
+)
+
+type (
+	// Compression is a compression algorithm of CompressCodec.
+	Compression byte
+
+	// CompressCodec is a Codec that compresses the values of another Codec above a size threshold.
+	// Values of any Compression are decoded, so the algorithm can be changed over time.
+	//
+	//	codec := rredis.NewCompressCodec(rredis.JSONCodec{}, rredis.Zstd, rredis.DefaultCompressThreshold)
+	//	users := rredis.NewCache[User](client, rredis.WithCodec(codec))
+	CompressCodec struct {
+		codec       Codec
+		compression Compression
+		threshold   int
+	}
+)
+
+// NewCompressCodec returns a CompressCodec that compresses the values of codec
+// larger than threshold bytes with compression.
+func NewCompressCodec(codec Codec, compression Compression, threshold int) *CompressCodec {
+	return &CompressCodec{
+		codec:       codec,
+		compression: compression,
+		threshold:   threshold,
+	}
+}
+
+// Marshal implements Codec.
+func (c *CompressCodec) Marshal(v interface{}) ([]byte, error) {
+	data, err := c.codec.Marshal(v)
+	if err != nil || len(data) <= c.threshold {
+		return data, err
+	}
+
+	buf := bytes.NewBuffer([]byte{compressedFlag})
+	switch c.compression {
+	case Gzip:
+		w := gzip.NewWriter(buf)
+		if _, err = w.Write(data); err != nil {
+			return nil, err
+		}
+		if err = w.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case Zstd:
+		initZstd()
+		return zstdEncoder.EncodeAll(data, buf.Bytes()), nil
+	default:
+		return nil, ErrUnknownCompression
+	}
+}
+
+// Unmarshal implements Codec.
+func (c *CompressCodec) Unmarshal(data []byte, v interface{}) error {
+	if len(data) == 0 || data[0] != compressedFlag {
+		return c.codec.Unmarshal(data, v)
+	}
+
+	data, err := decompress(data[1:])
+	if err != nil {
+		return err
+	}
+
+	return c.codec.Unmarshal(data, v)
+}
+
+func decompress(data []byte) ([]byte, error) {
+	switch {
+	case bytes.HasPrefix(data, gzipMagic):
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer r.Close()
+
+		return io.ReadAll(r)
+	case bytes.HasPrefix(data, zstdMagic):
+		initZstd()
+		return zstdDecoder.DecodeAll(data, nil)
+	default:
+		return nil, ErrUnknownCompression
+	}
+}
+
+func initZstd() {
+	zstdOnce.Do(func() {
+		// both can't fail without options.
+		zstdEncoder, _ = zstd.NewWriter(nil)
+		zstdDecoder, _ = zstd.NewReader(nil)
+	})
+}
@@ -0,0 +1,110 @@
+package rredis
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// encryptedFlag prefixes values encrypted by EncryptCodec, followed by the length of the key id,
+// the key id, the nonce and the sealed value.
+const encryptedFlag byte = 0xc2
+
+var (
+	// ErrUnknownKey is an error that indicates a value encrypted with a key id that's not configured.
+	ErrUnknownKey = errors.New("unknown encryption key")
+	// ErrInvalidCiphertext is an error that indicates a value not encrypted by EncryptCodec.
+	ErrInvalidCiphertext = errors.New("invalid encrypted value")
+)
+
+// EncryptCodec is a Codec that encrypts the values of another Codec with AES-GCM.
+// Values are encrypted with the current key and tagged with its id, so that keys can be rotated:
+// add the new key, make it current, and drop the old one once its values expired.
+// Wrap a CompressCodec to compress the values before they're encrypted.
+//
+//	codec, err := rredis.NewEncryptCodec(rredis.JSONCodec{}, "2024-06", map[string][]byte{
+//		"2024-01": oldKey,
+//		"2024-06": newKey,
+//	})
+type EncryptCodec struct {
+	codec Codec
+	keyID string
+	aeads map[string]cipher.AEAD
+}
+
+// NewEncryptCodec returns an EncryptCodec that encrypts the values of codec with the key keyID of keys,
+// and decrypts them with any of keys. Keys must be 16, 24 or 32 bytes long, key ids at most 255 bytes.
+func NewEncryptCodec(codec Codec, keyID string, keys map[string][]byte) (*EncryptCodec, error) {
+	if _, ok := keys[keyID]; !ok {
+		return nil, ErrUnknownKey
+	}
+
+	aeads := make(map[string]cipher.AEAD, len(keys))
+	for id, key := range keys {
+		if len(id) > 255 {
+			return nil, fmt.Errorf("encryption key id %q is too long", id)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %q: %w", id, err)
+		}
+		if aeads[id], err = cipher.NewGCM(block); err != nil {
+			return nil, err
+		}
+	}
+
+	return &EncryptCodec{
+		codec: codec,
+		keyID: keyID,
+		aeads: aeads,
+	}, nil
+}
+
+// Marshal implements Codec.
+func (c *EncryptCodec) Marshal(v interface{}) ([]byte, error) {
+	data, err := c.codec.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	aead := c.aeads[c.keyID]
+	header := make([]byte, 0, 2+len(c.keyID)+aead.NonceSize())
+	header = append(header, encryptedFlag, byte(len(c.keyID)))
+	header = append(header, c.keyID...)
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	// the header is authenticated, so that the key id can't be swapped.
+	return aead.Seal(append(header, nonce...), nonce, data, header), nil
+}
+
+// Unmarshal implements Codec.
+func (c *EncryptCodec) Unmarshal(data []byte, v interface{}) error {
+	if len(data) < 2 || data[0] != encryptedFlag || len(data) < 2+int(data[1]) {
+		return ErrInvalidCiphertext
+	}
+
+	header := data[:2+int(data[1])]
+	aead, ok := c.aeads[string(header[2:])]
+	if !ok {
+		return ErrUnknownKey
+	}
+
+	data = data[len(header):]
+	if len(data) < aead.NonceSize() {
+		return ErrInvalidCiphertext
+	}
+
+	plain, err := aead.Open(nil, data[:aead.NonceSize()], data[aead.NonceSize():], header)
+	if err != nil {
+		return ErrInvalidCiphertext
+	}
+
+	return c.codec.Unmarshal(plain, v)
+}
@@ -10,7 +10,7 @@ func (s *Redis) GeoAdd(key string, geoLocation ...*GeoLocation) (int64, error) {
 // GeoAddCtx is the implementation of redis geoadd command.
 func (s *Redis) GeoAddCtx(ctx context.Context, key string, geoLocation ...*GeoLocation) (
 	val int64, err error) {
-	return s.client.GeoAdd(ctx, key, geoLocation...).Result()
+	return s.client.GeoAdd(ctx, s.key(key), geoLocation...).Result()
 }
 
 // GeoDist is the implementation of redis geodist command.
@@ -21,7 +21,7 @@ func (s *Redis) GeoDist(key, member1, member2, unit string) (float64, error) {
 // GeoDistCtx is the implementation of redis geodist command.
 func (s *Redis) GeoDistCtx(ctx context.Context, key, member1, member2, unit string) (
 	val float64, err error) {
-	return s.client.GeoDist(ctx, key, member1, member2, unit).Result()
+	return s.client.GeoDist(ctx, s.key(key), member1, member2, unit).Result()
 }
 
 // GeoHash is the implementation of redis geohash command.
@@ -32,7 +32,7 @@ func (s *Redis) GeoHash(key string, members ...string) ([]string, error) {
 // GeoHashCtx is the implementation of redis geohash command.
 func (s *Redis) GeoHashCtx(ctx context.Context, key string, members ...string) (
 	val []string, err error) {
-	return s.client.GeoHash(ctx, key, members...).Result()
+	return s.client.GeoHash(ctx, s.key(key), members...).Result()
 }
 
 // GeoRadius is the implementation of redis georadius command.
@@ -44,7 +44,7 @@ func (s *Redis) GeoRadius(key string, longitude, latitude float64, query *GeoRad
 // GeoRadiusCtx is the implementation of redis georadius command.
 func (s *Redis) GeoRadiusCtx(ctx context.Context, key string, longitude, latitude float64,
 	query *GeoRadiusQuery) (val []GeoLocation, err error) {
-	return s.client.GeoRadius(ctx, key, longitude, latitude, query).Result()
+	return s.client.GeoRadius(ctx, s.key(key), longitude, latitude, query).Result()
 }
 
 // GeoRadiusByMember is the implementation of redis georadiusbymember command.
@@ -55,7 +55,7 @@ func (s *Redis) GeoRadiusByMember(key, member string, query *GeoRadiusQuery) ([]
 // GeoRadiusByMemberCtx is the implementation of redis georadiusbymember command.
 func (s *Redis) GeoRadiusByMemberCtx(ctx context.Context, key, member string,
 	query *GeoRadiusQuery) (val []GeoLocation, err error) {
-	return s.client.GeoRadiusByMember(ctx, key, member, query).Result()
+	return s.client.GeoRadiusByMember(ctx, s.key(key), member, query).Result()
 }
 
 // GeoPos is the implementation of redis geopos command.
@@ -66,5 +66,5 @@ func (s *Redis) GeoPos(key string, members ...string) ([]*GeoPos, error) {
 // GeoPosCtx is the implementation of redis geopos command.
 func (s *Redis) GeoPosCtx(ctx context.Context, key string, members ...string) (
 	val []*GeoPos, err error) {
-	return s.client.GeoPos(ctx, key, members...).Result()
+	return s.client.GeoPos(ctx, s.key(key), members...).Result()
 }
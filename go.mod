@@ -1,10 +1,15 @@
 module github.com/leafney/rose-redis
 
-go 1.17
+go 1.19
 
-require github.com/redis/go-redis/v9 v9.6.1
+require (
+	github.com/klauspost/compress v1.17.4
+	github.com/redis/go-redis/v9 v9.6.1
+	github.com/vmihailenco/msgpack/v5 v5.4.1
+)
 
 require (
 	github.com/cespare/xxhash/v2 v2.3.0 // indirect
 	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	github.com/vmihailenco/tagparser/v2 v2.0.0 // indirect
 )
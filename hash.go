@@ -9,7 +9,7 @@ func (s *Redis) HDel(key string, fields ...string) (bool, error) {
 
 // HDelCtx is the implementation of redis hdel command.
 func (s *Redis) HDelCtx(ctx context.Context, key string, fields ...string) (val bool, err error) {
-	v, err := s.client.HDel(ctx, key, fields...).Result()
+	v, err := s.client.HDel(ctx, s.key(key), fields...).Result()
 	return v >= 1, err
 }
 
@@ -20,7 +20,7 @@ func (s *Redis) HExists(key, field string) (bool, error) {
 
 // HExistsCtx is the implementation of redis hexists command.
 func (s *Redis) HExistsCtx(ctx context.Context, key, field string) (val bool, err error) {
-	return s.client.HExists(ctx, key, field).Result()
+	return s.client.HExists(ctx, s.key(key), field).Result()
 }
 
 // HGet is the implementation of redis hget command.
@@ -30,7 +30,7 @@ func (s *Redis) HGet(key, field string) (string, error) {
 
 // HGetCtx is the implementation of redis hget command.
 func (s *Redis) HGetCtx(ctx context.Context, key, field string) (val string, err error) {
-	return s.client.HGet(ctx, key, field).Result()
+	return s.client.HGet(ctx, s.key(key), field).Result()
 }
 
 // HGetAll is the implementation of redis hgetall command.
@@ -40,7 +40,11 @@ func (s *Redis) HGetAll(key string) (map[string]string, error) {
 
 // HGetAllCtx is the implementation of redis hgetall command.
 func (s *Redis) HGetAllCtx(ctx context.Context, key string) (val map[string]string, err error) {
-	return s.client.HGetAll(ctx, key).Result()
+	if s.tracker != nil {
+		return s.tracker.hgetall(ctx, s.key(key))
+	}
+
+	return s.client.HGetAll(ctx, s.key(key)).Result()
 }
 
 // HIncrBy is the implementation of redis hincrby command.
@@ -50,7 +54,7 @@ func (s *Redis) HIncrBy(key, field string, increment int64) (int64, error) {
 
 // HIncrByCtx is the implementation of redis hincrby command.
 func (s *Redis) HIncrByCtx(ctx context.Context, key, field string, increment int64) (val int64, err error) {
-	return s.client.HIncrBy(ctx, key, field, increment).Result()
+	return s.client.HIncrBy(ctx, s.key(key), field, increment).Result()
 }
 
 // HIncrByFloat is the implementation of redis hincrbyfloat command.
@@ -60,7 +64,7 @@ func (s *Redis) HIncrByFloat(key, field string, increment float64) (float64, err
 
 // HIncrByFloatCtx is the implementation of redis hincrbyfloat command.
 func (s *Redis) HIncrByFloatCtx(ctx context.Context, key, field string, increment float64) (val float64, err error) {
-	return s.client.HIncrByFloat(ctx, key, field, increment).Result()
+	return s.client.HIncrByFloat(ctx, s.key(key), field, increment).Result()
 }
 
 // HKeys is the implementation of redis hkeys command.
@@ -70,7 +74,7 @@ func (s *Redis) HKeys(key string) ([]string, error) {
 
 // HKeysCtx is the implementation of redis hkeys command.
 func (s *Redis) HKeysCtx(ctx context.Context, key string) (val []string, err error) {
-	return s.client.HKeys(ctx, key).Result()
+	return s.client.HKeys(ctx, s.key(key)).Result()
 }
 
 // HLen is the implementation of redis hlen command.
@@ -80,7 +84,7 @@ func (s *Redis) HLen(key string) (int64, error) {
 
 // HLenCtx is the implementation of redis hlen command.
 func (s *Redis) HLenCtx(ctx context.Context, key string) (val int64, err error) {
-	return s.client.HLen(ctx, key).Result()
+	return s.client.HLen(ctx, s.key(key)).Result()
 }
 
 // HMGet is the implementation of redis hmget command.
@@ -90,7 +94,7 @@ func (s *Redis) HMGet(key string, fields ...string) ([]string, error) {
 
 // HMGetCtx is the implementation of redis hmget command.
 func (s *Redis) HMGetCtx(ctx context.Context, key string, fields ...string) (val []string, err error) {
-	v, err := s.client.HMGet(ctx, key, fields...).Result()
+	v, err := s.client.HMGet(ctx, s.key(key), fields...).Result()
 	val = toStrings(v)
 	return
 }
@@ -102,7 +106,7 @@ func (s *Redis) HSet(key, field string, value interface{}) error {
 
 // HSetCtx is the implementation of redis hset command.
 func (s *Redis) HSetCtx(ctx context.Context, key, field string, value interface{}) error {
-	return s.client.HSet(ctx, key, field, value).Err()
+	return s.client.HSet(ctx, s.key(key), field, value).Err()
 }
 
 // HSetNX is the implementation of redis hsetnx command.
@@ -112,7 +116,7 @@ func (s *Redis) HSetNX(key, field string, value interface{}) (bool, error) {
 
 // HSetNXCtx is the implementation of redis hsetnx command.
 func (s *Redis) HSetNXCtx(ctx context.Context, key, field string, value interface{}) (val bool, err error) {
-	return s.client.HSetNX(ctx, key, field, value).Result()
+	return s.client.HSetNX(ctx, s.key(key), field, value).Result()
 }
 
 // HMSet is the implementation of redis hmset command.
@@ -127,7 +131,7 @@ func (s *Redis) HMSetCtx(ctx context.Context, key string, fieldsAndValues map[st
 		vals[k] = v
 	}
 
-	return s.client.HMSet(ctx, key, vals).Err()
+	return s.client.HMSet(ctx, s.key(key), vals).Err()
 }
 
 // HScan is the implementation of redis hscan command.
@@ -139,7 +143,7 @@ func (s *Redis) HScan(key string, cursor uint64, match string, count int64) (
 // HScanCtx is the implementation of redis hscan command.
 func (s *Redis) HScanCtx(ctx context.Context, key string, cursor uint64, match string, count int64) (
 	keys []string, cur uint64, err error) {
-	keys, cur, err = s.client.HScan(ctx, key, cursor, match, count).Result()
+	keys, cur, err = s.client.HScan(ctx, s.key(key), cursor, match, count).Result()
 	return
 }
 
@@ -150,6 +154,6 @@ func (s *Redis) HVals(key string) ([]string, error) {
 
 // HValsCtx is the implementation of redis hvals command.
 func (s *Redis) HValsCtx(ctx context.Context, key string) (val []string, err error) {
-	val, err = s.client.HVals(ctx, key).Result()
+	val, err = s.client.HVals(ctx, s.key(key)).Result()
 	return
 }
@@ -0,0 +1,164 @@
+package rredis
+
+import (
+	"context"
+	red "github.com/redis/go-redis/v9"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Logger is used to log slow commands.
+type Logger interface {
+	Printf(ctx context.Context, format string, v ...interface{})
+}
+
+type stdLogger struct{}
+
+func (stdLogger) Printf(_ context.Context, format string, v ...interface{}) {
+	log.Printf(format, v...)
+}
+
+// slowLogHook logs commands, pipelines and scripts slower than threshold.
+// Only the command name and key are logged unless logValues is set.
+// Blocking commands wait for data by design, they're not logged.
+type slowLogHook struct {
+	logger    Logger
+	threshold time.Duration
+	logValues bool
+}
+
+func newSlowLogHook(opt *Option) *slowLogHook {
+	logger := opt.Logger
+	if logger == nil {
+		logger = stdLogger{}
+	}
+
+	threshold := opt.SlowThreshold
+	if threshold == 0 {
+		threshold = defaultSlowThreshold
+	}
+
+	return &slowLogHook{
+		logger:    logger,
+		threshold: threshold,
+		logValues: opt.LogValues,
+	}
+}
+
+func (h *slowLogHook) DialHook(next red.DialHook) red.DialHook {
+	return next
+}
+
+func (h *slowLogHook) ProcessHook(next red.ProcessHook) red.ProcessHook {
+	return func(ctx context.Context, cmd red.Cmder) error {
+		if isBlocking(cmd) {
+			return next(ctx, cmd)
+		}
+
+		start := time.Now()
+		err := next(ctx, cmd)
+		if duration := time.Since(start); duration > h.threshold {
+			h.logger.Printf(ctx, "[REDIS] slow call, duration: %s, cmd: %s", duration, h.format(cmd))
+		}
+
+		return err
+	}
+}
+
+func (h *slowLogHook) ProcessPipelineHook(next red.ProcessPipelineHook) red.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []red.Cmder) error {
+		for _, cmd := range cmds {
+			if isBlocking(cmd) {
+				return next(ctx, cmds)
+			}
+		}
+
+		start := time.Now()
+		err := next(ctx, cmds)
+		if duration := time.Since(start); duration > h.threshold {
+			formatted := make([]string, len(cmds))
+			for i, cmd := range cmds {
+				formatted[i] = h.format(cmd)
+			}
+			h.logger.Printf(ctx, "[REDIS] slow pipeline, duration: %s, cmds: %s",
+				duration, strings.Join(formatted, ", "))
+		}
+
+		return err
+	}
+}
+
+// format returns the command name with its key, and the remaining args if values are logged.
+func (h *slowLogHook) format(cmd red.Cmder) string {
+	name := cmd.Name()
+	pos := cmdKeyPos(cmd)
+	if pos == 0 {
+		return name
+	}
+
+	args := cmd.Args()
+	if !h.logValues {
+		return name + " " + Repr(args[pos])
+	}
+
+	var b strings.Builder
+	b.WriteString(name)
+	for _, arg := range args[1:] {
+		b.WriteByte(' ')
+		b.WriteString(Repr(arg))
+	}
+
+	return b.String()
+}
+
+// isBlocking reports whether cmd may wait for data on the server, like BLPOP or XREAD with BLOCK.
+func isBlocking(cmd red.Cmder) bool {
+	switch cmd.Name() {
+	case "blpop", "brpop", "brpoplpush", "blmove", "blmpop", "bzpopmin", "bzpopmax", "bzmpop",
+		"wait", "waitaof", "subscribe", "psubscribe", "ssubscribe":
+		return true
+	case "xread", "xreadgroup":
+		for _, arg := range cmd.Args()[1:] {
+			if s, ok := arg.(string); ok && strings.EqualFold(s, "block") {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// cmdKey returns the first key of cmd, or an empty string if cmd has no key.
+func cmdKey(cmd red.Cmder) string {
+	if pos := cmdKeyPos(cmd); pos > 0 {
+		return Repr(cmd.Args()[pos])
+	}
+
+	return ""
+}
+
+// cmdKeyPos returns the position of the first key in the args of cmd, 0 means no key.
+func cmdKeyPos(cmd red.Cmder) int {
+	args := cmd.Args()
+	switch cmd.Name() {
+	case "eval", "evalsha", "eval_ro", "evalsha_ro", "fcall", "fcall_ro":
+		if len(args) > 3 {
+			if numKeys, err := strconv.Atoi(Repr(args[2])); err == nil && numKeys > 0 {
+				return 3
+			}
+		}
+		return 0
+	case "auth", "hello", "ping", "echo", "select", "client", "config", "info", "cluster",
+		"command", "script", "function", "time", "dbsize", "flushdb", "flushall",
+		"multi", "exec", "discard", "quit", "wait", "sentinel", "memory", "latency", "slowlog":
+		return 0
+	}
+
+	if len(args) > 1 {
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,93 @@
+package rredis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	red "github.com/redis/go-redis/v9"
+)
+
+type recordLogger struct {
+	lines []string
+}
+
+func (l *recordLogger) Printf(_ context.Context, format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func TestSlowLogHook(t *testing.T) {
+	ctx := context.Background()
+	logger := &recordLogger{}
+	h := newSlowLogHook(&Option{Logger: logger, SlowThreshold: time.Millisecond})
+	slow := func(ctx context.Context, cmd red.Cmder) error {
+		time.Sleep(2 * time.Millisecond)
+		return nil
+	}
+
+	tests := []struct {
+		cmd    red.Cmder
+		logged bool
+	}{
+		{cmd: red.NewStringCmd(ctx, "get", "user:1"), logged: true},
+		{cmd: red.NewStringSliceCmd(ctx, "blpop", "queue", 5), logged: false},
+		{cmd: red.NewStringCmd(ctx, "brpoplpush", "queue", "backup", 5), logged: false},
+		{cmd: red.NewXStreamSliceCmd(ctx, "xreadgroup", "group", "g", "c", "block", 0, "streams", "s", ">"), logged: false},
+		{cmd: red.NewXStreamSliceCmd(ctx, "xread", "count", 10, "streams", "s", "0"), logged: true},
+	}
+	for _, test := range tests {
+		logger.lines = nil
+		_ = h.ProcessHook(slow)(ctx, test.cmd)
+		if logged := len(logger.lines) > 0; logged != test.logged {
+			t.Errorf("%s: expected logged %v, got %v", test.cmd.Name(), test.logged, logger.lines)
+		}
+	}
+
+	logger.lines = nil
+	_ = h.ProcessHook(func(ctx context.Context, cmd red.Cmder) error {
+		return nil
+	})(ctx, red.NewStringCmd(ctx, "get", "user:1"))
+	if len(logger.lines) > 0 {
+		t.Fatalf("expected fast commands not logged, got %v", logger.lines)
+	}
+
+	logger.lines = nil
+	pipeline := func(ctx context.Context, cmds []red.Cmder) error {
+		time.Sleep(2 * time.Millisecond)
+		return nil
+	}
+	_ = h.ProcessPipelineHook(pipeline)(ctx, []red.Cmder{
+		red.NewStringCmd(ctx, "get", "user:1"),
+		red.NewStatusCmd(ctx, "set", "user:2", "bob"),
+	})
+	if len(logger.lines) != 1 || !strings.HasSuffix(logger.lines[0], "cmds: get user:1, set user:2") {
+		t.Fatalf("expected the slow pipeline logged, got %v", logger.lines)
+	}
+
+	logger.lines = nil
+	_ = h.ProcessPipelineHook(pipeline)(ctx, []red.Cmder{
+		red.NewStatusCmd(ctx, "set", "user:2", "bob"),
+		red.NewStringSliceCmd(ctx, "blpop", "queue", 5),
+	})
+	if len(logger.lines) > 0 {
+		t.Fatalf("expected pipelines with blocking commands not logged, got %v", logger.lines)
+	}
+}
+
+func TestSlowLogFormat(t *testing.T) {
+	ctx := context.Background()
+	cmd := red.NewStatusCmd(ctx, "set", "user:1", "secret")
+	if s := newSlowLogHook(&Option{}).format(cmd); s != "set user:1" {
+		t.Fatalf("expected the values hidden, got %q", s)
+	}
+	if s := newSlowLogHook(&Option{LogValues: true}).format(cmd); s != "set user:1 secret" {
+		t.Fatalf("expected the values logged, got %q", s)
+	}
+
+	eval := red.NewCmd(ctx, "eval", "return 1", 1, "lock", "token")
+	if s := newSlowLogHook(&Option{}).format(eval); s != "eval lock" {
+		t.Fatalf("expected the key of the script, got %q", s)
+	}
+}
@@ -9,7 +9,7 @@ func (s *Redis) PFAdd(key string, values ...interface{}) (bool, error) {
 
 // PFAddCtx is the implementation of redis pfadd command.
 func (s *Redis) PFAddCtx(ctx context.Context, key string, values ...interface{}) (val bool, err error) {
-	v, err := s.client.PFAdd(ctx, key, values...).Result()
+	v, err := s.client.PFAdd(ctx, s.key(key), values...).Result()
 	return v >= 1, err
 }
 
@@ -20,7 +20,7 @@ func (s *Redis) PFCount(key string) (int64, error) {
 
 // PFCountCtx is the implementation of redis pfcount command.
 func (s *Redis) PFCountCtx(ctx context.Context, key string) (val int64, err error) {
-	return s.client.PFCount(ctx, key).Result()
+	return s.client.PFCount(ctx, s.key(key)).Result()
 }
 
 // PFMerge is the implementation of redis pfmerge command.
@@ -30,6 +30,6 @@ func (s *Redis) PFMerge(dest string, keys ...string) error {
 
 // PFMergeCtx is the implementation of redis pfmerge command.
 func (s *Redis) PFMergeCtx(ctx context.Context, dest string, keys ...string) error {
-	_, err := s.client.PFMerge(ctx, dest, keys...).Result()
+	_, err := s.client.PFMerge(ctx, s.key(dest), s.keys(keys)...).Result()
 	return err
 }
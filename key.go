@@ -12,7 +12,7 @@ func (s *Redis) Del(keys ...string) (int64, error) {
 
 // DelCtx deletes keys.
 func (s *Redis) DelCtx(ctx context.Context, keys ...string) (val int64, err error) {
-	return s.client.Del(ctx, keys...).Result()
+	return s.client.Del(ctx, s.keys(keys)...).Result()
 }
 
 // Exists is the implementation of redis exists command.
@@ -22,7 +22,7 @@ func (s *Redis) Exists(key string) (bool, error) {
 
 // ExistsCtx is the implementation of redis exists command.
 func (s *Redis) ExistsCtx(ctx context.Context, key string) (val bool, err error) {
-	v, err := s.client.Exists(ctx, key).Result()
+	v, err := s.client.Exists(ctx, s.key(key)).Result()
 	if err != nil {
 		return false, err
 	}
@@ -38,7 +38,7 @@ func (s *Redis) Expire(key string, seconds int64) error {
 
 // ExpireCtx is the implementation of redis expire command.
 func (s *Redis) ExpireCtx(ctx context.Context, key string, seconds int64) error {
-	return s.client.Expire(ctx, key, time.Duration(seconds)*time.Second).Err()
+	return s.client.Expire(ctx, s.key(key), time.Duration(seconds)*time.Second).Err()
 }
 
 // ExpireAt is the implementation of redis expireat command.
@@ -48,7 +48,7 @@ func (s *Redis) ExpireAt(key string, expireTime int64) error {
 
 // ExpireAtCtx is the implementation of redis expireat command.
 func (s *Redis) ExpireAtCtx(ctx context.Context, key string, expireTime int64) error {
-	return s.client.ExpireAt(ctx, key, time.Unix(expireTime, 0)).Err()
+	return s.client.ExpireAt(ctx, s.key(key), time.Unix(expireTime, 0)).Err()
 }
 
 // Keys is the implementation of redis keys command.
@@ -58,7 +58,9 @@ func (s *Redis) Keys(pattern string) ([]string, error) {
 
 // KeysCtx is the implementation of redis keys command.
 func (s *Redis) KeysCtx(ctx context.Context, pattern string) (val []string, err error) {
-	return s.client.Keys(ctx, pattern).Result()
+	val, err = s.client.Keys(ctx, s.key(pattern)).Result()
+	val = s.stripKeys(val)
+	return
 }
 
 // Persist is the implementation of redis persist command.
@@ -68,7 +70,7 @@ func (s *Redis) Persist(key string) (bool, error) {
 
 // PersistCtx is the implementation of redis persist command.
 func (s *Redis) PersistCtx(ctx context.Context, key string) (val bool, err error) {
-	return s.client.Persist(ctx, key).Result()
+	return s.client.Persist(ctx, s.key(key)).Result()
 }
 
 // TTL is the implementation of redis ttl command.
@@ -78,7 +80,7 @@ func (s *Redis) TTL(key string) (int64, error) {
 
 // TTLCtx is the implementation of redis ttl command.
 func (s *Redis) TTLCtx(ctx context.Context, key string) (val int64, err error) {
-	duration, err := s.client.TTL(ctx, key).Result()
+	duration, err := s.client.TTL(ctx, s.key(key)).Result()
 	if err != nil {
 		return -1, err
 	}
@@ -94,6 +96,10 @@ func (s *Redis) Scan(cursor uint64, match string, count int64) (keys []string, c
 // ScanCtx is the implementation of redis scan command.
 func (s *Redis) ScanCtx(ctx context.Context, cursor uint64, match string, count int64) (
 	keys []string, cur uint64, err error) {
-	keys, cur, err = s.client.Scan(ctx, cursor, match, count).Result()
+	if len(match) == 0 && len(s.prefix) > 0 {
+		match = "*"
+	}
+	keys, cur, err = s.client.Scan(ctx, cursor, s.key(match), count).Result()
+	keys = s.stripKeys(keys)
 	return
 }
@@ -0,0 +1,243 @@
+package rredis
+
+import (
+	"context"
+	"sync"
+)
+
+const (
+	readersSuffix = ":readers"
+	writerSuffix  = ":writer"
+
+	// leaseClock sets now to the server time in milliseconds, so that the leases
+	// don't depend on the clocks of the holders, and defines the helpers of the lease scripts.
+	leaseClock = `redis.replicate_commands()
+local time = redis.call("TIME")
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+local function trim(key)
+	redis.call("ZREMRANGEBYSCORE", key, "-inf", now)
+	return redis.call("ZCARD", key)
+end
+local function hold(key, token, ttl)
+	redis.call("ZADD", key, now + ttl, token)
+	local last = redis.call("ZRANGE", key, -1, -1, "WITHSCORES")
+	redis.call("PEXPIREAT", key, last[2])
+	return 1
+end
+`
+	// readLockScript adds ARGV[1] to the readers KEYS[1] for ARGV[2] ms, unless the writer KEYS[2] is held.
+	readLockScript = leaseClock + `trim(KEYS[1])
+if trim(KEYS[2]) > 0 then
+	return 0
+end
+return hold(KEYS[1], ARGV[1], tonumber(ARGV[2]))`
+	// writeLockScript sets ARGV[1] as the writer KEYS[2] for ARGV[2] ms, if there's no reader in KEYS[1] nor writer.
+	writeLockScript = leaseClock + `if trim(KEYS[1]) > 0 or trim(KEYS[2]) > 0 then
+	return 0
+end
+return hold(KEYS[2], ARGV[1], tonumber(ARGV[2]))`
+	// semaphoreScript adds ARGV[1] to the holders KEYS[1] for ARGV[2] ms, if there are less than ARGV[3].
+	semaphoreScript = leaseClock + `if trim(KEYS[1]) >= tonumber(ARGV[3]) then
+	return 0
+end
+return hold(KEYS[1], ARGV[1], tonumber(ARGV[2]))`
+	// extendLeaseScript renews the lease of ARGV[1] in KEYS[1] for ARGV[2] ms, if it's not expired.
+	extendLeaseScript = leaseClock + `local expireAt = redis.call("ZSCORE", KEYS[1], ARGV[1])
+if not expireAt or tonumber(expireAt) <= now then
+	return 0
+end
+return hold(KEYS[1], ARGV[1], tonumber(ARGV[2]))`
+	// releaseLeaseScript removes ARGV[1] from KEYS[1], it returns 0 if its lease expired.
+	releaseLeaseScript = leaseClock + `local expireAt = redis.call("ZSCORE", KEYS[1], ARGV[1])
+redis.call("ZREM", KEYS[1], ARGV[1])
+if not expireAt or tonumber(expireAt) <= now then
+	return 0
+end
+return 1`
+)
+
+type (
+	// lease is a holder record in a sorted set scored by its expiry,
+	// expired holders are removed by the next scripts on the set, so crashed holders are reclaimed.
+	lease struct {
+		rds  *Redis
+		opts *lockOptions
+
+		lock     sync.Mutex
+		key      string
+		token    string
+		watchdog watchdog
+	}
+
+	// RWMutex is a distributed reader/writer lock: many readers or one writer hold it at a time.
+	// Each RWMutex is one holder, reading or writing. Readers and the writer are leases
+	// that expire unless they're extended, see WithWatchdog. New readers are let in
+	// while a writer waits, so a writer may starve under a steady flow of readers.
+	//
+	//	rw := client.NewRWMutex("reports", rredis.WithWatchdog())
+	//	if err := rw.RLock(ctx); err != nil {
+	//		return err
+	//	}
+	//	defer rw.RUnlock(context.Background())
+	RWMutex struct {
+		lease
+		readers string
+		writer  string
+	}
+
+	// Semaphore is a distributed counting semaphore: at most limit holders hold it at a time.
+	// Each Semaphore is one holder, its lease expires unless it's extended, see WithWatchdog.
+	//
+	//	sem := client.NewSemaphore("jobs:export", 4)
+	//	if err := sem.Acquire(ctx); err != nil {
+	//		return err
+	//	}
+	//	defer sem.Release(context.Background())
+	Semaphore struct {
+		lease
+		holders string
+		limit   int
+	}
+)
+
+// NewRWMutex returns a RWMutex on key, its readers and writer are kept in keys suffixed
+// with :readers and :writer in the same cluster slot.
+func (s *Redis) NewRWMutex(key string, opts ...LockOption) *RWMutex {
+	key = s.key(key)
+	return &RWMutex{
+		lease: lease{
+			rds:  s,
+			opts: newLockOptions(opts...),
+		},
+		readers: sameSlotKey(key, readersSuffix),
+		writer:  sameSlotKey(key, writerSuffix),
+	}
+}
+
+// TryRLock acquires the lock for reading if there's no writer, without waiting.
+func (m *RWMutex) TryRLock(ctx context.Context) (bool, error) {
+	return m.acquire(ctx, readLockScript, m.readers, []string{m.readers, m.writer})
+}
+
+// RLock acquires the lock for reading, retrying with backoff until there's no writer or ctx is done.
+func (m *RWMutex) RLock(ctx context.Context) error {
+	return retryLock(ctx, m.opts, m.TryRLock)
+}
+
+// RUnlock releases the lock held for reading, it returns ErrLockNotHeld if it expired.
+func (m *RWMutex) RUnlock(ctx context.Context) error {
+	return m.release(ctx)
+}
+
+// TryLock acquires the lock for writing if there's no reader nor writer, without waiting.
+func (m *RWMutex) TryLock(ctx context.Context) (bool, error) {
+	return m.acquire(ctx, writeLockScript, m.writer, []string{m.readers, m.writer})
+}
+
+// Lock acquires the lock for writing, retrying with backoff until it's free or ctx is done.
+func (m *RWMutex) Lock(ctx context.Context) error {
+	return retryLock(ctx, m.opts, m.TryLock)
+}
+
+// Unlock releases the lock held for writing, it returns ErrLockNotHeld if it expired.
+func (m *RWMutex) Unlock(ctx context.Context) error {
+	return m.release(ctx)
+}
+
+// NewSemaphore returns a Semaphore on key with at most limit holders.
+func (s *Redis) NewSemaphore(key string, limit int, opts ...LockOption) *Semaphore {
+	return &Semaphore{
+		lease: lease{
+			rds:  s,
+			opts: newLockOptions(opts...),
+		},
+		holders: s.key(key),
+		limit:   limit,
+	}
+}
+
+// TryAcquire acquires the semaphore if it has less than limit holders, without waiting.
+func (m *Semaphore) TryAcquire(ctx context.Context) (bool, error) {
+	return m.acquire(ctx, semaphoreScript, m.holders, []string{m.holders}, m.limit)
+}
+
+// Acquire acquires the semaphore, retrying with backoff until it has less than limit holders or ctx is done.
+func (m *Semaphore) Acquire(ctx context.Context) error {
+	return retryLock(ctx, m.opts, m.TryAcquire)
+}
+
+// Release releases the semaphore, it returns ErrLockNotHeld if the lease expired.
+func (m *Semaphore) Release(ctx context.Context) error {
+	return m.release(ctx)
+}
+
+// acquire runs script with keys to add a new holder to the sorted set key.
+func (l *lease) acquire(ctx context.Context, script, key string, keys []string, args ...interface{}) (bool, error) {
+	token := randomToken()
+	args = append([]interface{}{token, l.opts.expire.Milliseconds()}, args...)
+	n, err := l.rds.client.Eval(ctx, script, keys, args...).Int64()
+	if err != nil || n == 0 {
+		return false, err
+	}
+
+	l.lock.Lock()
+	l.key = key
+	l.token = token
+	l.lock.Unlock()
+
+	if l.opts.watchdog {
+		l.watchdog.start(l.rds.ctx, l.opts.expire/3, l.Extend)
+	}
+
+	return true, nil
+}
+
+// Extend renews the lease of the holder, it returns ErrLockNotHeld if it expired,
+// and the lease is no longer held.
+func (l *lease) Extend(ctx context.Context) error {
+	l.lock.Lock()
+	key, token := l.key, l.token
+	l.lock.Unlock()
+
+	if len(token) == 0 {
+		return ErrLockNotHeld
+	}
+
+	err := l.eval(ctx, extendLeaseScript, key, token, l.opts.expire.Milliseconds())
+	if err == ErrLockNotHeld {
+		l.lock.Lock()
+		if l.token == token {
+			l.key, l.token = "", ""
+		}
+		l.lock.Unlock()
+	}
+
+	return err
+}
+
+func (l *lease) release(ctx context.Context) error {
+	l.watchdog.stopAndWait()
+
+	l.lock.Lock()
+	key, token := l.key, l.token
+	l.key, l.token = "", ""
+	l.lock.Unlock()
+
+	if len(token) == 0 {
+		return ErrLockNotHeld
+	}
+
+	return l.eval(ctx, releaseLeaseScript, key, token)
+}
+
+func (l *lease) eval(ctx context.Context, script, key string, args ...interface{}) error {
+	n, err := l.rds.client.Eval(ctx, script, []string{key}, args...).Int64()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrLockNotHeld
+	}
+
+	return nil
+}
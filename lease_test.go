@@ -0,0 +1,33 @@
+package rredis
+
+import (
+	"context"
+	"testing"
+)
+
+func TestNewRWMutex(t *testing.T) {
+	rds := &Redis{prefix: "app:"}
+	for _, key := range []string{"reports", "{user:1}:profile"} {
+		rw := rds.NewRWMutex(key)
+		if keySlot(rw.readers) != keySlot(rw.writer) {
+			t.Errorf("expected the readers and writer of %q in the same slot, got %q and %q", key, rw.readers, rw.writer)
+		}
+	}
+}
+
+func TestLeaseNotHeld(t *testing.T) {
+	rds := &Redis{prefix: "app:"}
+	sem := rds.NewSemaphore("jobs", 4)
+	if sem.holders != "app:jobs" || sem.limit != 4 {
+		t.Fatalf("unexpected semaphore %q of %d", sem.holders, sem.limit)
+	}
+
+	if err := sem.Release(context.Background()); err != ErrLockNotHeld {
+		t.Fatalf("expected ErrLockNotHeld, got %v", err)
+	}
+	if err := rds.NewRWMutex("reports").Extend(context.Background()); err != ErrLockNotHeld {
+		t.Fatalf("expected ErrLockNotHeld, got %v", err)
+	}
+
+	var _ Locker = rds.NewRWMutex("reports")
+}
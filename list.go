@@ -34,14 +34,14 @@ func (s *Redis) BlpopExCtx(ctx context.Context, node RedisNode, key string) (str
 
 */
 
-// BLPop Execute blocking queries with default timeout 5 seconds
+// BLPop Execute blocking queries with the blocking timeout, 5 seconds by default
 func (s *Redis) BLPop(key string) (string, error) {
 	return s.BLPopCtx(s.ctx, key)
 }
 
-// BLPopCtx Execute blocking queries with default timeout 5 seconds
+// BLPopCtx Execute blocking queries with the blocking timeout, 5 seconds by default
 func (s *Redis) BLPopCtx(ctx context.Context, key string) (string, error) {
-	return s.BLPopWithTimeoutCtx(ctx, blockingQueryTimeout, key)
+	return s.BLPopWithTimeoutCtx(ctx, s.blockingTimeout, key)
 }
 
 // BLPopWithTimeout Execute blocking queries with timeout
@@ -51,7 +51,7 @@ func (s *Redis) BLPopWithTimeout(timeout time.Duration, keys ...string) (string,
 
 // BLPopWithTimeoutCtx Execute blocking queries with timeout
 func (s *Redis) BLPopWithTimeoutCtx(ctx context.Context, timeout time.Duration, keys ...string) (string, error) {
-	val, err := s.client.BLPop(ctx, timeout, keys...).Result()
+	val, err := s.client.BLPop(ctx, timeout, s.keys(keys)...).Result()
 	if err != nil {
 		return "", err
 	}
@@ -63,14 +63,14 @@ func (s *Redis) BLPopWithTimeoutCtx(ctx context.Context, timeout time.Duration,
 	return val[1], nil
 }
 
-// BRPop Execute blocking queries with default timeout 5 seconds
+// BRPop Execute blocking queries with the blocking timeout, 5 seconds by default
 func (s *Redis) BRPop(key string) (string, error) {
 	return s.BRPopCtx(s.ctx, key)
 }
 
-// BRPopCtx Execute blocking queries with default timeout 5 seconds
+// BRPopCtx Execute blocking queries with the blocking timeout, 5 seconds by default
 func (s *Redis) BRPopCtx(ctx context.Context, key string) (string, error) {
-	return s.BRPopWithTimeoutCtx(ctx, blockingQueryTimeout, key)
+	return s.BRPopWithTimeoutCtx(ctx, s.blockingTimeout, key)
 }
 
 // BRPopWithTimeout Execute blocking queries with timeout
@@ -80,7 +80,7 @@ func (s *Redis) BRPopWithTimeout(timeout time.Duration, keys ...string) (string,
 
 // BRPopWithTimeoutCtx Execute blocking queries with timeout
 func (s *Redis) BRPopWithTimeoutCtx(ctx context.Context, timeout time.Duration, keys ...string) (string, error) {
-	val, err := s.client.BRPop(ctx, timeout, keys...).Result()
+	val, err := s.client.BRPop(ctx, timeout, s.keys(keys)...).Result()
 	if err != nil {
 		return "", err
 	}
@@ -94,12 +94,12 @@ func (s *Redis) BRPopWithTimeoutCtx(ctx context.Context, timeout time.Duration,
 
 // BRPopLPush
 func (s *Redis) BRPopLPush(sourceKey string, destKey string, timeout time.Duration) (string, error) {
-	return s.client.BRPopLPush(s.ctx, sourceKey, destKey, timeout).Result()
+	return s.client.BRPopLPush(s.ctx, s.key(sourceKey), s.key(destKey), timeout).Result()
 }
 
 // BRPopLPushCtx
 func (s *Redis) BRPopLPushCtx(ctx context.Context, sourceKey string, destKey string, timeout time.Duration) (string, error) {
-	return s.client.BRPopLPush(ctx, sourceKey, destKey, timeout).Result()
+	return s.client.BRPopLPush(ctx, s.key(sourceKey), s.key(destKey), timeout).Result()
 }
 
 // LIndex is the implementation of redis lindex command.
@@ -109,7 +109,7 @@ func (s *Redis) LIndex(key string, index int64) (string, error) {
 
 // LIndexCtx is the implementation of redis lindex command.
 func (s *Redis) LIndexCtx(ctx context.Context, key string, index int64) (val string, err error) {
-	return s.client.LIndex(ctx, key, index).Result()
+	return s.client.LIndex(ctx, s.key(key), index).Result()
 }
 
 // TODO Linsert
@@ -121,7 +121,7 @@ func (s *Redis) LLen(key string) (int64, error) {
 
 // LLenCtx is the implementation of redis llen command.
 func (s *Redis) LLenCtx(ctx context.Context, key string) (val int64, err error) {
-	return s.client.LLen(ctx, key).Result()
+	return s.client.LLen(ctx, s.key(key)).Result()
 }
 
 // LPop is the implementation of redis lpop command.
@@ -131,7 +131,7 @@ func (s *Redis) LPop(key string) (string, error) {
 
 // LPopCtx is the implementation of redis lpop command.
 func (s *Redis) LPopCtx(ctx context.Context, key string) (val string, err error) {
-	return s.client.LPop(ctx, key).Result()
+	return s.client.LPop(ctx, s.key(key)).Result()
 }
 
 // LPopCount is the implementation of redis lpopCount command.
@@ -141,7 +141,7 @@ func (s *Redis) LPopCount(key string, count int) ([]string, error) {
 
 // LPopCountCtx is the implementation of redis lpopCount command.
 func (s *Redis) LPopCountCtx(ctx context.Context, key string, count int) (val []string, err error) {
-	return s.client.LPopCount(ctx, key, count).Result()
+	return s.client.LPopCount(ctx, s.key(key), count).Result()
 }
 
 // LPush is the implementation of redis lpush command.
@@ -151,7 +151,7 @@ func (s *Redis) LPush(key string, values ...interface{}) (int64, error) {
 
 // LPushCtx is the implementation of redis lpush command.
 func (s *Redis) LPushCtx(ctx context.Context, key string, values ...interface{}) (val int64, err error) {
-	return s.client.LPush(ctx, key, values...).Result()
+	return s.client.LPush(ctx, s.key(key), values...).Result()
 }
 
 // LRange is the implementation of redis lrange command.
@@ -161,7 +161,7 @@ func (s *Redis) LRange(key string, start, stop int64) ([]string, error) {
 
 // LRangeCtx is the implementation of redis lrange command.
 func (s *Redis) LRangeCtx(ctx context.Context, key string, start, stop int64) (val []string, err error) {
-	return s.client.LRange(ctx, key, start, stop).Result()
+	return s.client.LRange(ctx, s.key(key), start, stop).Result()
 }
 
 // LRem is the implementation of redis lrem command.
@@ -171,7 +171,7 @@ func (s *Redis) LRem(key string, count int64, value string) (int64, error) {
 
 // LRemCtx is the implementation of redis lrem command.
 func (s *Redis) LRemCtx(ctx context.Context, key string, count int64, value string) (val int64, err error) {
-	return s.client.LRem(ctx, key, count, value).Result()
+	return s.client.LRem(ctx, s.key(key), count, value).Result()
 }
 
 // TODO Lset
@@ -183,7 +183,7 @@ func (s *Redis) LTrim(key string, start, stop int64) error {
 
 // LTrimCtx is the implementation of redis ltrim command.
 func (s *Redis) LTrimCtx(ctx context.Context, key string, start, stop int64) error {
-	return s.client.LTrim(ctx, key, start, stop).Err()
+	return s.client.LTrim(ctx, s.key(key), start, stop).Err()
 }
 
 // RPop is the implementation of redis rpop command.
@@ -193,7 +193,7 @@ func (s *Redis) RPop(key string) (string, error) {
 
 // RPopCtx is the implementation of redis rpop command.
 func (s *Redis) RPopCtx(ctx context.Context, key string) (val string, err error) {
-	return s.client.RPop(ctx, key).Result()
+	return s.client.RPop(ctx, s.key(key)).Result()
 }
 
 // RPopCount is the implementation of redis rpopCount command.
@@ -203,7 +203,7 @@ func (s *Redis) RPopCount(key string, count int) ([]string, error) {
 
 // RPopCountCtx is the implementation of redis rpopCount command.
 func (s *Redis) RPopCountCtx(ctx context.Context, key string, count int) (val []string, err error) {
-	return s.client.RPopCount(ctx, key, count).Result()
+	return s.client.RPopCount(ctx, s.key(key), count).Result()
 }
 
 // RPush is the implementation of redis rpush command.
@@ -213,5 +213,5 @@ func (s *Redis) RPush(key string, values ...interface{}) (int64, error) {
 
 // RPushCtx is the implementation of redis rpush command.
 func (s *Redis) RPushCtx(ctx context.Context, key string, values ...interface{}) (val int64, err error) {
-	return s.client.RPush(ctx, key, values...).Result()
+	return s.client.RPush(ctx, s.key(key), values...).Result()
 }
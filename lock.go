@@ -0,0 +1,328 @@
+package rredis
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLockExpire = 30 * time.Second
+	minLockBackoff    = 10 * time.Millisecond
+	maxLockBackoff    = 500 * time.Millisecond
+	fenceSuffix       = ":fence"
+
+	// acquireScript sets the lock to the token if it's free, and returns the next fencing token.
+	acquireScript = `if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("INCR", KEYS[2])
+end
+return 0`
+	// extendScript resets the expiration of the lock only if it's still held by the given token.
+	extendScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`
+)
+
+// ErrLockNotHeld is an error that indicates a lock released or extended after it was lost.
+var ErrLockNotHeld = errors.New("lock not held")
+
+type (
+	// Locker is a distributed lock, implemented by Mutex on one node and Redlock on several.
+	Locker interface {
+		// TryLock acquires the lock if it's free, without waiting.
+		TryLock(ctx context.Context) (bool, error)
+		// Lock acquires the lock, retrying with backoff until it's free or ctx is done.
+		Lock(ctx context.Context) error
+		// Unlock releases the lock, it returns ErrLockNotHeld if it was lost.
+		Unlock(ctx context.Context) error
+		// Extend resets the expiration of the lock, it returns ErrLockNotHeld if it was lost.
+		Extend(ctx context.Context) error
+	}
+
+	// LockOption customizes the locks.
+	LockOption func(*lockOptions)
+
+	lockOptions struct {
+		expire     time.Duration
+		watchdog   bool
+		minBackoff time.Duration
+		maxBackoff time.Duration
+		// nodeTimeout is the time given to each node of a Redlock by an attempt.
+		nodeTimeout time.Duration
+	}
+
+	// Mutex is a distributed lock on a redis key, held with a random token until it expires.
+	// Each acquisition gets a fencing token greater than the previous ones,
+	// storage written under the lock can reject writes with older tokens.
+	// A Mutex is held by one owner at a time, it isn't reentrant.
+	//
+	//	mu := client.NewMutex("jobs:report", rredis.WithLockExpire(10*time.Second), rredis.WithWatchdog())
+	//	if err := mu.Lock(ctx); err != nil {
+	//		return err
+	//	}
+	//	defer mu.Unlock(context.Background())
+	Mutex struct {
+		rds  *Redis
+		key  string
+		opts *lockOptions
+
+		lock     sync.Mutex
+		token    string
+		fence    int64
+		watchdog watchdog
+	}
+
+	// watchdog renews a lock periodically until it's stopped or the lock is lost,
+	// extend forgets the lock when it's lost.
+	watchdog struct {
+		lock sync.Mutex
+		stop context.CancelFunc
+		done chan struct{}
+	}
+)
+
+// WithLockExpire sets the expiration of locks, 30 seconds by default.
+func WithLockExpire(expire time.Duration) LockOption {
+	return func(o *lockOptions) {
+		o.expire = expire
+	}
+}
+
+// WithWatchdog makes locks renewed every third of their expiration while they're held,
+// so that they expire only if their owner dies.
+func WithWatchdog() LockOption {
+	return func(o *lockOptions) {
+		o.watchdog = true
+	}
+}
+
+// WithLockBackoff sets the bounds of the exponential backoff between attempts of Lock,
+// 10ms and 500ms by default.
+func WithLockBackoff(minBackoff, maxBackoff time.Duration) LockOption {
+	return func(o *lockOptions) {
+		o.minBackoff = minBackoff
+		o.maxBackoff = maxBackoff
+	}
+}
+
+// WithNodeTimeout sets the time given to each node of a Redlock to acquire or extend the lock,
+// the smaller of a tenth of the expiration and 50ms by default. Raise it for nodes behind TLS
+// or far away, releases aren't bound by it. The timeout only cuts off a hung node when
+// its client is created WithContextTimeout, otherwise go-redis waits for its read timeout.
+func WithNodeTimeout(timeout time.Duration) LockOption {
+	return func(o *lockOptions) {
+		o.nodeTimeout = timeout
+	}
+}
+
+func newLockOptions(opts ...LockOption) *lockOptions {
+	o := &lockOptions{
+		expire:     defaultLockExpire,
+		minBackoff: minLockBackoff,
+		maxBackoff: maxLockBackoff,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// NewMutex returns a Mutex on key.
+func (s *Redis) NewMutex(key string, opts ...LockOption) *Mutex {
+	return &Mutex{
+		rds:  s,
+		key:  key,
+		opts: newLockOptions(opts...),
+	}
+}
+
+// TryLock acquires the lock if it's free, without waiting.
+func (m *Mutex) TryLock(ctx context.Context) (bool, error) {
+	token := randomToken()
+	fence, err := m.acquire(ctx, token)
+	if err != nil || fence == 0 {
+		return false, err
+	}
+
+	m.lock.Lock()
+	m.token = token
+	m.fence = fence
+	m.lock.Unlock()
+
+	if m.opts.watchdog {
+		m.watchdog.start(m.rds.ctx, m.opts.expire/3, m.Extend)
+	}
+
+	return true, nil
+}
+
+// Lock acquires the lock, retrying with backoff until it's free or ctx is done.
+func (m *Mutex) Lock(ctx context.Context) error {
+	return retryLock(ctx, m.opts, m.TryLock)
+}
+
+// Unlock releases the lock, it returns ErrLockNotHeld if it expired or was taken by another owner.
+func (m *Mutex) Unlock(ctx context.Context) error {
+	m.watchdog.stopAndWait()
+
+	m.lock.Lock()
+	token := m.token
+	m.token = ""
+	m.lock.Unlock()
+
+	if len(token) == 0 {
+		return ErrLockNotHeld
+	}
+
+	return m.release(ctx, token)
+}
+
+// Extend resets the expiration of the lock, it returns ErrLockNotHeld
+// if it expired or was taken by another owner, and the lock is no longer held.
+func (m *Mutex) Extend(ctx context.Context) error {
+	m.lock.Lock()
+	token := m.token
+	m.lock.Unlock()
+
+	if len(token) == 0 {
+		return ErrLockNotHeld
+	}
+
+	err := m.extend(ctx, token)
+	if err == ErrLockNotHeld {
+		m.lock.Lock()
+		if m.token == token {
+			m.token = ""
+		}
+		m.lock.Unlock()
+	}
+
+	return err
+}
+
+// Token returns the random token of the current owner, empty if the lock isn't held,
+// or was found lost by Extend or the watchdog.
+func (m *Mutex) Token() string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.token
+}
+
+// FencingToken returns the fencing token of the last acquisition of the lock.
+func (m *Mutex) FencingToken() int64 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.fence
+}
+
+// acquire sets the lock to token if it's free, it returns the fencing token, 0 if the lock is taken.
+func (m *Mutex) acquire(ctx context.Context, token string) (int64, error) {
+	key := m.rds.key(m.key)
+	return m.rds.client.Eval(ctx, acquireScript, []string{key, fenceKey(key)}, token,
+		m.opts.expire.Milliseconds()).Int64()
+}
+
+func (m *Mutex) release(ctx context.Context, token string) error {
+	n, err := m.rds.EvalCtx(ctx, releaseScript, []string{m.key}, token)
+	if err != nil {
+		return err
+	}
+	if n.(int64) == 0 {
+		return ErrLockNotHeld
+	}
+
+	return nil
+}
+
+func (m *Mutex) extend(ctx context.Context, token string) error {
+	n, err := m.rds.EvalCtx(ctx, extendScript, []string{m.key}, token, m.opts.expire.Milliseconds())
+	if err != nil {
+		return err
+	}
+	if n.(int64) == 0 {
+		return ErrLockNotHeld
+	}
+
+	return nil
+}
+
+// start calls extend every interval until stopped or extend returns ErrLockNotHeld,
+// a running watchdog is stopped first.
+func (w *watchdog) start(ctx context.Context, interval time.Duration, extend func(ctx context.Context) error) {
+	w.stopAndWait()
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	w.lock.Lock()
+	w.stop = cancel
+	w.done = done
+	w.lock.Unlock()
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// transient errors are retried on the next tick, the lock is lost otherwise.
+				if err := extend(ctx); err == ErrLockNotHeld {
+					return
+				}
+			}
+		}
+	}()
+}
+
+func (w *watchdog) stopAndWait() {
+	w.lock.Lock()
+	stop, done := w.stop, w.done
+	w.stop, w.done = nil, nil
+	w.lock.Unlock()
+
+	if stop != nil {
+		stop()
+		<-done
+	}
+}
+
+// retryLock calls tryLock with exponential backoff and jitter until it succeeds or ctx is done.
+func retryLock(ctx context.Context, o *lockOptions, tryLock func(ctx context.Context) (bool, error)) error {
+	backoff := o.minBackoff
+	for {
+		ok, err := tryLock(ctx)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+
+		wait := backoff/2 + time.Duration(rand.Int63n(int64(backoff/2)+1))
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+
+		if backoff *= 2; backoff > o.maxBackoff {
+			backoff = o.maxBackoff
+		}
+	}
+}
+
+// fenceKey returns the key of the fencing counter of the prefixed lock key, in the same cluster slot.
+func fenceKey(key string) string {
+	return sameSlotKey(key, fenceSuffix)
+}
@@ -0,0 +1,182 @@
+package rredis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	red "github.com/redis/go-redis/v9"
+)
+
+func TestFenceKey(t *testing.T) {
+	for _, key := range []string{"jobs:report", "app:{user:1}:lock", "foo{bar"} {
+		if keySlot(fenceKey(key)) != keySlot(key) {
+			t.Errorf("expected the fence key of %q in the same slot, got %q", key, fenceKey(key))
+		}
+	}
+}
+
+func TestRetryLock(t *testing.T) {
+	o := newLockOptions(WithLockBackoff(time.Millisecond, 4*time.Millisecond))
+
+	var attempts int
+	err := retryLock(context.Background(), o, func(ctx context.Context) (bool, error) {
+		attempts++
+		return attempts == 3, nil
+	})
+	if err != nil || attempts != 3 {
+		t.Fatalf("expected the lock on the third attempt, got %d attempts, %v", attempts, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = retryLock(ctx, o, func(ctx context.Context) (bool, error) {
+		return false, nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to stop the retries, got %v", err)
+	}
+
+	boom := errors.New("boom")
+	if err = retryLock(context.Background(), o, func(ctx context.Context) (bool, error) {
+		return false, boom
+	}); err != boom {
+		t.Fatalf("expected the error, got %v", err)
+	}
+}
+
+func TestRedlockQuorum(t *testing.T) {
+	nodes := make([]*Redis, 5)
+	l := NewRedlock(nodes, "lock", WithLockExpire(time.Second))
+	if l.quorum != 3 {
+		t.Fatalf("expected a quorum of 3, got %d", l.quorum)
+	}
+	if drift := l.drift(); drift != 12*time.Millisecond {
+		t.Fatalf("unexpected clock drift %s", drift)
+	}
+	if l.Validity() != 0 {
+		t.Fatal("expected no validity before the lock is acquired")
+	}
+
+	var _ Locker = l
+	var _ Locker = new(Mutex)
+}
+
+// takenHook answers every command with 0, like a node where the lock is held by another owner.
+type takenHook struct{}
+
+func (takenHook) DialHook(next red.DialHook) red.DialHook {
+	return next
+}
+
+func (takenHook) ProcessHook(next red.ProcessHook) red.ProcessHook {
+	return func(ctx context.Context, cmd red.Cmder) error {
+		cmd.(*red.Cmd).SetVal(int64(0))
+		return nil
+	}
+}
+
+func (takenHook) ProcessPipelineHook(next red.ProcessPipelineHook) red.ProcessPipelineHook {
+	return next
+}
+
+func TestRedlockFailedNodes(t *testing.T) {
+	down := func() *Redis {
+		return FromClient(red.NewClient(&red.Options{Addr: "127.0.0.1:1", MaxRetries: -1}))
+	}
+	taken := func() *Redis {
+		c := red.NewClient(&red.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+		c.AddHook(takenHook{})
+		return FromClient(c)
+	}
+
+	l := NewRedlock([]*Redis{down(), taken(), taken(), taken(), taken()}, "lock")
+	if ok, err := l.TryLock(context.Background()); ok || err != nil {
+		t.Fatalf("expected the lock taken with a node down, got %v, %v", ok, err)
+	}
+
+	l = NewRedlock([]*Redis{down(), down(), down(), taken(), taken()}, "lock")
+	ok, err := l.TryLock(context.Background())
+	var opErr *net.OpError
+	if ok || !errors.As(err, &opErr) {
+		t.Fatalf("expected the error of the nodes down, got %v, %v", ok, err)
+	}
+}
+
+func TestRedlockHungNode(t *testing.T) {
+	// the node accepts the connections, but never answers.
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+
+	opt := loadOption(&Option{MaxRetries: -1, MinIdleConns: -1, ContextTimeout: true})
+	hung := FromClient(red.NewUniversalClient(newUniversalOptions(ln.Addr().String(), opt)))
+	defer hung.Close()
+	taken := func() *Redis {
+		c := red.NewClient(&red.Options{Addr: "127.0.0.1:1", MaxRetries: -1})
+		c.AddHook(takenHook{})
+		return FromClient(c)
+	}
+
+	l := NewRedlock([]*Redis{hung, taken(), taken()}, "lock", WithNodeTimeout(50*time.Millisecond))
+	start := time.Now()
+	if ok, err := l.TryLock(context.Background()); ok || err != nil {
+		t.Fatalf("expected the lock taken with a node hung, got %v, %v", ok, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected the hung node cut off by the node timeout, took %s", elapsed)
+	}
+}
+
+func TestRedlockNodeTimeout(t *testing.T) {
+	if timeout := NewRedlock(nil, "lock", WithLockExpire(time.Second)).nodeTimeout(); timeout != 50*time.Millisecond {
+		t.Fatalf("unexpected default node timeout %s", timeout)
+	}
+	if timeout := NewRedlock(nil, "lock", WithLockExpire(200*time.Millisecond)).nodeTimeout(); timeout != 20*time.Millisecond {
+		t.Fatalf("unexpected node timeout %s", timeout)
+	}
+	if timeout := NewRedlock(nil, "lock", WithNodeTimeout(time.Second)).nodeTimeout(); timeout != time.Second {
+		t.Fatalf("unexpected node timeout %s", timeout)
+	}
+}
+
+func TestMutexLost(t *testing.T) {
+	rds, _ := newMemoryRedis()
+	m := rds.NewMutex("lock")
+
+	// the memory redis has no lock, extending it finds it lost.
+	m.token = "token"
+	if err := m.Extend(context.Background()); err != ErrLockNotHeld {
+		t.Fatalf("expected ErrLockNotHeld, got %v", err)
+	}
+	if token := m.Token(); token != "" {
+		t.Fatalf("expected the lost lock forgotten, got token %q", token)
+	}
+
+	m.token = "token"
+	m.watchdog.start(context.Background(), time.Millisecond, m.Extend)
+	defer m.watchdog.stopAndWait()
+	deadline := time.Now().Add(time.Second)
+	for m.Token() != "" {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the watchdog to forget the lost lock")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	if err := m.Unlock(context.Background()); err != ErrLockNotHeld {
+		t.Fatalf("expected ErrLockNotHeld, got %v", err)
+	}
+}
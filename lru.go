@@ -0,0 +1,108 @@
+package rredis
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+type (
+	// lruCache is a bounded in-memory cache with per-entry expiration,
+	// the least recently used entry is evicted when it's full.
+	lruCache[V any] struct {
+		lock    sync.Mutex
+		size    int
+		ttl     time.Duration
+		entries map[string]*list.Element
+		order   *list.List
+	}
+
+	lruEntry[V any] struct {
+		key      string
+		val      V
+		expireAt time.Time
+	}
+)
+
+func newLruCache[V any](size int, ttl time.Duration) *lruCache[V] {
+	return &lruCache[V]{
+		size:    size,
+		ttl:     ttl,
+		entries: make(map[string]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// Get returns the value of key if it's cached and not expired.
+func (c *lruCache[V]) Get(key string) (val V, ok bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	elem, ok := c.entries[key]
+	if !ok {
+		return val, false
+	}
+
+	entry := elem.Value.(*lruEntry[V])
+	if time.Now().After(entry.expireAt) {
+		c.remove(elem)
+		return val, false
+	}
+
+	c.order.MoveToFront(elem)
+	return entry.val, true
+}
+
+// Set caches val with key for the ttl of c.
+func (c *lruCache[V]) Set(key string, val V) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	expireAt := time.Now().Add(c.ttl)
+	if elem, ok := c.entries[key]; ok {
+		entry := elem.Value.(*lruEntry[V])
+		entry.val = val
+		entry.expireAt = expireAt
+		c.order.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry[V]{key: key, val: val, expireAt: expireAt})
+	for c.order.Len() > c.size {
+		c.remove(c.order.Back())
+	}
+}
+
+// Del removes keys.
+func (c *lruCache[V]) Del(keys ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, key := range keys {
+		if elem, ok := c.entries[key]; ok {
+			c.remove(elem)
+		}
+	}
+}
+
+// Flush removes all the entries.
+func (c *lruCache[V]) Flush() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.entries = make(map[string]*list.Element)
+	c.order.Init()
+}
+
+// Len returns the number of entries, including the expired ones not evicted yet.
+func (c *lruCache[V]) Len() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return c.order.Len()
+}
+
+func (c *lruCache[V]) remove(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*lruEntry[V]).key)
+}
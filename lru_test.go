@@ -0,0 +1,45 @@
+package rredis
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLruCache(t *testing.T) {
+	c := newLruCache[int](2, time.Minute)
+	c.Set("a", 1)
+	c.Set("b", 2)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("expected a to be cached")
+	}
+
+	c.Set("c", 3)
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("expected b, the least recently used, to be evicted")
+	}
+	if val, ok := c.Get("c"); !ok || val != 3 {
+		t.Fatalf("unexpected c: %d, %v", val, ok)
+	}
+
+	c.Del("a")
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("expected a to be deleted")
+	}
+
+	c.Flush()
+	if c.Len() != 0 {
+		t.Fatalf("expected empty cache, got %d entries", c.Len())
+	}
+}
+
+func TestLruCacheExpire(t *testing.T) {
+	c := newLruCache[string](2, time.Millisecond)
+	c.Set("a", "x")
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("expected a to be expired")
+	}
+	if c.Len() != 0 {
+		t.Fatalf("expected the expired entry to be removed, got %d entries", c.Len())
+	}
+}
@@ -0,0 +1,121 @@
+package rredis
+
+import (
+	"context"
+	red "github.com/redis/go-redis/v9"
+	"time"
+)
+
+const (
+	// StatusOk means the command succeeded.
+	StatusOk = "ok"
+	// StatusNil means the command succeeded with a nil reply, like get on a missing key.
+	StatusNil = "nil"
+	// StatusError means the command failed.
+	StatusError = "error"
+
+	// CacheHit means a cached value or tombstone was found.
+	CacheHit = "hit"
+	// CacheMiss means the key wasn't cached.
+	CacheMiss = "miss"
+	// CacheLoad means a loader was called, successfully or with ErrNotFound.
+	CacheLoad = "load"
+	// CacheLoadError means a loader failed.
+	CacheLoadError = "load_error"
+	// CacheStaleServe means an expired value was served during its grace window.
+	CacheStaleServe = "stale"
+
+	defaultStatsInterval = 10 * time.Second
+)
+
+type (
+	// PoolStats is an alias of redis.PoolStats.
+	PoolStats = red.PoolStats
+
+	// MetricsRecorder records the metrics of redis commands and the connection pool.
+	MetricsRecorder interface {
+		// ObserveCommand records a command, status is one of StatusOk, StatusNil and StatusError.
+		// The commands of a pipeline are recorded each with its own status and the latency of the pipeline,
+		// and the pipeline itself is recorded as the pipeline command, failed if any command failed.
+		ObserveCommand(cmd, status string, duration time.Duration)
+		// ObservePoolStats records the stats of the connection pool, it's called periodically.
+		ObservePoolStats(stats *PoolStats)
+	}
+
+	// CacheMetricsRecorder is implemented by the MetricsRecorders that also record the cache stats,
+	// see Redis.Stats.
+	CacheMetricsRecorder interface {
+		// ObserveCache records count cache events of namespace, the key prefix of the Redis.
+		// event is one of CacheHit, CacheMiss, CacheLoad, CacheLoadError and CacheStaleServe,
+		// duration is the latency of loads, zero for other events.
+		ObserveCache(namespace, event string, count int, duration time.Duration)
+	}
+)
+
+type metricsHook struct {
+	recorder MetricsRecorder
+}
+
+func (h metricsHook) DialHook(next red.DialHook) red.DialHook {
+	return next
+}
+
+func (h metricsHook) ProcessHook(next red.ProcessHook) red.ProcessHook {
+	return func(ctx context.Context, cmd red.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		h.recorder.ObserveCommand(cmd.Name(), cmdStatus(err), time.Since(start))
+		return err
+	}
+}
+
+func (h metricsHook) ProcessPipelineHook(next red.ProcessPipelineHook) red.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []red.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		duration := time.Since(start)
+
+		status := StatusOk
+		for _, cmd := range cmds {
+			s := cmdStatus(cmd.Err())
+			if s == StatusError {
+				status = StatusError
+			}
+			h.recorder.ObserveCommand(cmd.Name(), s, duration)
+		}
+		// a failed connection may leave the commands without errors.
+		if err != nil && err != Nil {
+			status = StatusError
+		}
+		h.recorder.ObserveCommand("pipeline", status, duration)
+
+		return err
+	}
+}
+
+func cmdStatus(err error) string {
+	switch err {
+	case nil:
+		return StatusOk
+	case Nil:
+		return StatusNil
+	default:
+		return StatusError
+	}
+}
+
+// reportPoolStats reports the pool stats of client every interval until ctx is done.
+func reportPoolStats(ctx context.Context, client red.UniversalClient, recorder MetricsRecorder,
+	interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			recorder.ObservePoolStats(client.PoolStats())
+		}
+	}
+}
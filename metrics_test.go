@@ -0,0 +1,54 @@
+package rredis
+
+import (
+	"context"
+	"errors"
+	red "github.com/redis/go-redis/v9"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMetricsHook(t *testing.T) {
+	recorder := NewPrometheusRecorder("app", 0.01, 0.1)
+	hook := metricsHook{recorder: recorder}
+	ctx := context.Background()
+
+	for _, err := range []error{nil, Nil, errors.New("boom")} {
+		process := hook.ProcessHook(func(ctx context.Context, cmd red.Cmder) error {
+			return err
+		})
+		if got := process(ctx, red.NewStringCmd(ctx, "get", "k")); got != err {
+			t.Errorf("expected %v, got %v", err, got)
+		}
+	}
+	pipeline := hook.ProcessPipelineHook(func(ctx context.Context, cmds []red.Cmder) error {
+		cmds[1].SetErr(Nil)
+		return Nil
+	})
+	_ = pipeline(ctx, []red.Cmder{red.NewStatusCmd(ctx, "set", "k", "v"), red.NewStringCmd(ctx, "get", "missing")})
+	recorder.ObserveCommand("set", StatusOk, 50*time.Millisecond)
+	recorder.ObservePoolStats(&PoolStats{Hits: 3, TotalConns: 2})
+
+	var b strings.Builder
+	if _, err := recorder.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, line := range []string{
+		`app_redis_commands_total{cmd="get",status="error"} 1`,
+		`app_redis_commands_total{cmd="get",status="nil"} 2`,
+		`app_redis_commands_total{cmd="set",status="ok"} 2`,
+		`app_redis_commands_total{cmd="pipeline",status="ok"} 1`,
+		`app_redis_commands_total{cmd="get",status="ok"} 1`,
+		`app_redis_command_duration_seconds_bucket{cmd="set",le="0.01"} 1`,
+		`app_redis_command_duration_seconds_bucket{cmd="set",le="0.1"} 2`,
+		`app_redis_command_duration_seconds_count{cmd="get"} 4`,
+		`app_redis_pool_hits_total 3`,
+		`app_redis_pool_total_conns 2`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("missing %q in:\n%s", line, out)
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package rredis
+
+import "strings"
+
+// WithNamespace returns a view of s that prefixes every key with prefix,
+// the prefix is appended to the prefix of s, if any.
+// The view shares the connections of s, closing either closes both.
+//
+//	users := client.WithNamespace("users:")
+//	users.Set("1", "tom") // sets key users:1
+func (s *Redis) WithNamespace(prefix string) *Redis {
+	r := *s
+	r.prefix = s.prefix + prefix
+	return &r
+}
+
+// Key returns key with the namespace prefix of s.
+// Commands in Pipelined are sent as is, use Key to build their keys.
+func (s *Redis) Key(key string) string {
+	return s.key(key)
+}
+
+func (s *Redis) key(key string) string {
+	if len(s.prefix) == 0 {
+		return key
+	}
+
+	return s.prefix + key
+}
+
+func (s *Redis) keys(keys []string) []string {
+	if len(s.prefix) == 0 {
+		return keys
+	}
+
+	ret := make([]string, len(keys))
+	for i, key := range keys {
+		ret[i] = s.prefix + key
+	}
+
+	return ret
+}
+
+func (s *Redis) stripKeys(keys []string) []string {
+	if len(s.prefix) == 0 {
+		return keys
+	}
+
+	for i, key := range keys {
+		keys[i] = strings.TrimPrefix(key, s.prefix)
+	}
+
+	return keys
+}
+
+// pairs prefixes the keys of key/value pairs, given as key1, value1, key2, value2
+// or as a single map or slice like go-redis accepts.
+func (s *Redis) pairs(values []interface{}) []interface{} {
+	if len(s.prefix) == 0 {
+		return values
+	}
+
+	if len(values) == 1 {
+		switch vals := values[0].(type) {
+		case []interface{}:
+			return s.pairs(vals)
+		case []string:
+			ret := make([]interface{}, len(vals))
+			for i, val := range vals {
+				ret[i] = val
+			}
+			return s.pairs(ret)
+		case map[string]interface{}:
+			ret := make([]interface{}, 0, len(vals)*2)
+			for k, v := range vals {
+				ret = append(ret, s.prefix+k, v)
+			}
+			return ret
+		case map[string]string:
+			ret := make([]interface{}, 0, len(vals)*2)
+			for k, v := range vals {
+				ret = append(ret, s.prefix+k, v)
+			}
+			return ret
+		}
+	}
+
+	ret := make([]interface{}, len(values))
+	for i, val := range values {
+		if i%2 == 0 {
+			ret[i] = s.prefix + Repr(val)
+		} else {
+			ret[i] = val
+		}
+	}
+
+	return ret
+}
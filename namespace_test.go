@@ -0,0 +1,28 @@
+package rredis
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWithNamespace(t *testing.T) {
+	r := (&Redis{prefix: "app:"}).WithNamespace("users:")
+	if key := r.Key("1"); key != "app:users:1" {
+		t.Errorf("unexpected key %q", key)
+	}
+
+	expected := []interface{}{"app:users:1", "tom", "app:users:2", 2}
+	if pairs := r.pairs([]interface{}{"1", "tom", "2", 2}); !reflect.DeepEqual(pairs, expected) {
+		t.Errorf("unexpected pairs %v", pairs)
+	}
+	if pairs := r.pairs([]interface{}{[]interface{}{"1", "tom", "2", 2}}); !reflect.DeepEqual(pairs, expected) {
+		t.Errorf("unexpected pairs %v", pairs)
+	}
+	if pairs := r.pairs([]interface{}{map[string]interface{}{"1": "tom"}}); !reflect.DeepEqual(pairs, expected[:2]) {
+		t.Errorf("unexpected pairs %v", pairs)
+	}
+
+	if keys := r.stripKeys([]string{"app:users:1", "app:users:2"}); !reflect.DeepEqual(keys, []string{"1", "2"}) {
+		t.Errorf("unexpected keys %v", keys)
+	}
+}
@@ -0,0 +1,126 @@
+package rredis
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	red "github.com/redis/go-redis/v9"
+)
+
+// resubscribeBackoff is the pause after a failed receive of invalidations.
+const resubscribeBackoff = time.Second
+
+// nearCache is the in-memory cache in front of a Cache, kept coherent across instances
+// by invalidation messages published on a redis channel.
+// Messages are the publisher id followed by the invalidated key.
+type nearCache[T any] struct {
+	*lruCache[T]
+	rds     *Redis
+	id      string
+	channel string
+	pubsub  *red.PubSub
+	done    chan struct{}
+
+	lock sync.Mutex
+	// seq is increased by every invalidation, values read before it are dropped.
+	seq uint64
+}
+
+func newNearCache[T any](rds *Redis, o *cacheOptions) *nearCache[T] {
+	c := &nearCache[T]{
+		lruCache: newLruCache[T](o.localSize, o.localExpire),
+		rds:      rds,
+		id:       randomToken(),
+		channel:  o.channel,
+		pubsub:   rds.client.Subscribe(rds.ctx, o.channel),
+		done:     make(chan struct{}),
+	}
+	go c.listen()
+
+	return c
+}
+
+// Invalidate drops keys from the local caches of the other instances.
+func (c *nearCache[T]) Invalidate(ctx context.Context, keys ...string) error {
+	_, err := c.rds.client.Pipelined(ctx, func(pipe red.Pipeliner) error {
+		for _, key := range keys {
+			pipe.Publish(ctx, c.channel, c.id+key)
+		}
+		return nil
+	})
+
+	return err
+}
+
+// current returns the invalidation sequence, to be read before the values passed to store.
+func (c *nearCache[T]) current() uint64 {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.seq
+}
+
+// store caches the value of key read at seq, unless it's been invalidated since
+// and the value read may be older than the invalidation.
+func (c *nearCache[T]) store(seq uint64, key string, val T) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.seq == seq {
+		c.Set(key, val)
+	}
+}
+
+// drop deletes keys, or the whole cache without keys, and drops the values being read.
+func (c *nearCache[T]) drop(keys ...string) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.seq++
+	if len(keys) == 0 {
+		c.Flush()
+	} else {
+		c.Del(keys...)
+	}
+}
+
+// Close stops listening to invalidations.
+func (c *nearCache[T]) Close() error {
+	err := c.pubsub.Close()
+	<-c.done
+	return err
+}
+
+// listen drops the invalidated keys until the subscription is closed.
+// The whole local cache is flushed on every (re)subscription, as messages may have been missed.
+func (c *nearCache[T]) listen() {
+	defer close(c.done)
+
+	for {
+		msg, err := c.pubsub.Receive(c.rds.ctx)
+		if errors.Is(err, red.ErrClosed) {
+			return
+		}
+		if err != nil {
+			c.drop()
+			time.Sleep(resubscribeBackoff)
+			continue
+		}
+
+		c.receive(msg)
+	}
+}
+
+// receive handles a message of the subscription, the messages published by c are skipped.
+func (c *nearCache[T]) receive(msg interface{}) {
+	switch m := msg.(type) {
+	case *red.Subscription:
+		c.drop()
+	case *red.Message:
+		if len(m.Payload) < len(c.id) || m.Payload[:len(c.id)] == c.id {
+			return
+		}
+		c.drop(m.Payload[len(c.id):])
+	}
+}
@@ -0,0 +1,98 @@
+package rredis
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	red "github.com/redis/go-redis/v9"
+)
+
+// newTestNearCache returns a nearCache of rds with the id self, without subscribing.
+func newTestNearCache[T any](rds *Redis) *nearCache[T] {
+	return &nearCache[T]{
+		lruCache: newLruCache[T](10, time.Minute),
+		rds:      rds,
+		id:       "self",
+		channel:  "invalidations",
+	}
+}
+
+func TestNearCacheReceive(t *testing.T) {
+	c := newTestNearCache[int](nil)
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	c.receive(&red.Message{Channel: c.channel, Payload: "peera"})
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("expected a invalidated by another instance")
+	}
+	c.receive(&red.Message{Channel: c.channel, Payload: "selfb"})
+	if _, ok := c.Get("b"); !ok {
+		t.Fatal("expected the messages published by the cache itself skipped")
+	}
+
+	c.receive(&red.Subscription{Kind: "subscribe", Channel: c.channel, Count: 1})
+	if c.Len() != 0 {
+		t.Fatalf("expected the cache flushed on subscription, got %d entries", c.Len())
+	}
+}
+
+func TestNearCacheStore(t *testing.T) {
+	c := newTestNearCache[int](nil)
+
+	seq := c.current()
+	c.receive(&red.Message{Channel: c.channel, Payload: "peera"})
+	c.store(seq, "a", 1)
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("expected the value read before the invalidation dropped")
+	}
+
+	c.store(c.current(), "a", 2)
+	if val, ok := c.Get("a"); !ok || val != 2 {
+		t.Fatalf("expected the value read after the invalidation cached, got %d, %v", val, ok)
+	}
+}
+
+func TestNearCacheInvalidate(t *testing.T) {
+	rds, h := newMemoryRedis()
+	c := newTestNearCache[int](rds)
+
+	if err := c.Invalidate(context.Background(), "a", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if len(h.published) != 2 || h.published[0] != "selfa" || h.published[1] != "selfb" {
+		t.Fatalf("expected the keys published after the id, got %q", h.published)
+	}
+}
+
+func TestCacheGetInvalidatedWhileReading(t *testing.T) {
+	rds, h := newMemoryRedis()
+	cache := NewCache[string](rds)
+	cache.local = newTestNearCache[string](rds)
+	if err := cache.set(context.Background(), "user:1", "ann", 0); err != nil {
+		t.Fatal(err)
+	}
+	cache.local.Flush()
+
+	// another instance writes and invalidates the key while it's read.
+	h.before = func(cmd red.Cmder) {
+		if cmd.Name() == "get" {
+			cache.local.receive(&red.Message{Channel: cache.local.channel, Payload: "peeruser:1"})
+		}
+	}
+	if val, err := cache.Get(context.Background(), "user:1"); err != nil || val != "ann" {
+		t.Fatalf("expected the value read, got %q, %v", val, err)
+	}
+	if _, ok := cache.local.Get("user:1"); ok {
+		t.Fatal("expected the value read before the invalidation kept out of the local cache")
+	}
+
+	h.before = nil
+	if _, err := cache.Get(context.Background(), "user:1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.local.Get("user:1"); !ok {
+		t.Fatal("expected the value read after the invalidation cached")
+	}
+}
@@ -0,0 +1,134 @@
+package rredis
+
+import (
+	"context"
+	"errors"
+)
+
+// IsNotFound tells if err means a missing key, field or member, that is Nil,
+// or ErrNotFound returned by the cache helpers.
+func IsNotFound(err error) bool {
+	return errors.Is(err, Nil) || errors.Is(err, ErrNotFound)
+}
+
+// GetOk is the implementation of redis get command, ok is false if key doesn't exist.
+func (s *Redis) GetOk(key string) (string, bool, error) {
+	return s.GetOkCtx(s.ctx, key)
+}
+
+// GetOkCtx is the implementation of redis get command, ok is false if key doesn't exist.
+func (s *Redis) GetOkCtx(ctx context.Context, key string) (val string, ok bool, err error) {
+	return okResult(s.client.Get(ctx, s.key(key)).Result())
+}
+
+// GetSetOk is the implementation of redis getset command, ok is false if key didn't exist.
+func (s *Redis) GetSetOk(key string, value interface{}) (string, bool, error) {
+	return s.GetSetOkCtx(s.ctx, key, value)
+}
+
+// GetSetOkCtx is the implementation of redis getset command, ok is false if key didn't exist.
+func (s *Redis) GetSetOkCtx(ctx context.Context, key string, value interface{}) (val string, ok bool, err error) {
+	return okResult(s.client.GetSet(ctx, s.key(key), value).Result())
+}
+
+// HGetOk is the implementation of redis hget command, ok is false if key or field doesn't exist.
+func (s *Redis) HGetOk(key, field string) (string, bool, error) {
+	return s.HGetOkCtx(s.ctx, key, field)
+}
+
+// HGetOkCtx is the implementation of redis hget command, ok is false if key or field doesn't exist.
+func (s *Redis) HGetOkCtx(ctx context.Context, key, field string) (val string, ok bool, err error) {
+	return okResult(s.client.HGet(ctx, s.key(key), field).Result())
+}
+
+// LIndexOk is the implementation of redis lindex command, ok is false if index is out of range.
+func (s *Redis) LIndexOk(key string, index int64) (string, bool, error) {
+	return s.LIndexOkCtx(s.ctx, key, index)
+}
+
+// LIndexOkCtx is the implementation of redis lindex command, ok is false if index is out of range.
+func (s *Redis) LIndexOkCtx(ctx context.Context, key string, index int64) (val string, ok bool, err error) {
+	return okResult(s.client.LIndex(ctx, s.key(key), index).Result())
+}
+
+// LPopOk is the implementation of redis lpop command, ok is false if the list is empty.
+func (s *Redis) LPopOk(key string) (string, bool, error) {
+	return s.LPopOkCtx(s.ctx, key)
+}
+
+// LPopOkCtx is the implementation of redis lpop command, ok is false if the list is empty.
+func (s *Redis) LPopOkCtx(ctx context.Context, key string) (val string, ok bool, err error) {
+	return okResult(s.client.LPop(ctx, s.key(key)).Result())
+}
+
+// RPopOk is the implementation of redis rpop command, ok is false if the list is empty.
+func (s *Redis) RPopOk(key string) (string, bool, error) {
+	return s.RPopOkCtx(s.ctx, key)
+}
+
+// RPopOkCtx is the implementation of redis rpop command, ok is false if the list is empty.
+func (s *Redis) RPopOkCtx(ctx context.Context, key string) (val string, ok bool, err error) {
+	return okResult(s.client.RPop(ctx, s.key(key)).Result())
+}
+
+// SPopOk is the implementation of redis spop command, ok is false if the set is empty.
+func (s *Redis) SPopOk(key string) (string, bool, error) {
+	return s.SPopOkCtx(s.ctx, key)
+}
+
+// SPopOkCtx is the implementation of redis spop command, ok is false if the set is empty.
+func (s *Redis) SPopOkCtx(ctx context.Context, key string) (val string, ok bool, err error) {
+	return okResult(s.client.SPop(ctx, s.key(key)).Result())
+}
+
+// SRandMemberOk is the implementation of redis srandmember command, ok is false if the set is empty.
+func (s *Redis) SRandMemberOk(key string) (string, bool, error) {
+	return s.SRandMemberOkCtx(s.ctx, key)
+}
+
+// SRandMemberOkCtx is the implementation of redis srandmember command, ok is false if the set is empty.
+func (s *Redis) SRandMemberOkCtx(ctx context.Context, key string) (val string, ok bool, err error) {
+	return okResult(s.client.SRandMember(ctx, s.key(key)).Result())
+}
+
+// ZScoreOk is the implementation of redis zscore command, ok is false if key or member doesn't exist.
+func (s *Redis) ZScoreOk(key, member string) (float64, bool, error) {
+	return s.ZScoreOkCtx(s.ctx, key, member)
+}
+
+// ZScoreOkCtx is the implementation of redis zscore command, ok is false if key or member doesn't exist.
+func (s *Redis) ZScoreOkCtx(ctx context.Context, key, member string) (val float64, ok bool, err error) {
+	return okResult(s.client.ZScore(ctx, s.key(key), member).Result())
+}
+
+// ZRankOk is the implementation of redis zrank command, ok is false if key or member doesn't exist.
+func (s *Redis) ZRankOk(key, member string) (int64, bool, error) {
+	return s.ZRankOkCtx(s.ctx, key, member)
+}
+
+// ZRankOkCtx is the implementation of redis zrank command, ok is false if key or member doesn't exist.
+func (s *Redis) ZRankOkCtx(ctx context.Context, key, member string) (val int64, ok bool, err error) {
+	return okResult(s.client.ZRank(ctx, s.key(key), member).Result())
+}
+
+// ZRevRankOk is the implementation of redis zrevrank command, ok is false if key or member doesn't exist.
+func (s *Redis) ZRevRankOk(key, member string) (int64, bool, error) {
+	return s.ZRevRankOkCtx(s.ctx, key, member)
+}
+
+// ZRevRankOkCtx is the implementation of redis zrevrank command, ok is false if key or member doesn't exist.
+func (s *Redis) ZRevRankOkCtx(ctx context.Context, key, member string) (val int64, ok bool, err error) {
+	return okResult(s.client.ZRevRank(ctx, s.key(key), member).Result())
+}
+
+// okResult turns Nil into ok being false.
+func okResult[T any](val T, err error) (T, bool, error) {
+	if err == Nil {
+		return val, false, nil
+	}
+	if err != nil {
+		return val, false, err
+	}
+
+	return val, true, nil
+}
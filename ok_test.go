@@ -0,0 +1,32 @@
+package rredis
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestOkResult(t *testing.T) {
+	if val, ok, err := okResult("", Nil); ok || err != nil || val != "" {
+		t.Fatalf("expected missing, got %q, %v, %v", val, ok, err)
+	}
+	if val, ok, err := okResult("", nil); !ok || err != nil || val != "" {
+		t.Fatalf("expected an empty value, got %q, %v, %v", val, ok, err)
+	}
+
+	boom := errors.New("boom")
+	if _, ok, err := okResult(1.5, boom); ok || err != boom {
+		t.Fatalf("expected the error, got %v, %v", ok, err)
+	}
+}
+
+func TestIsNotFound(t *testing.T) {
+	for _, err := range []error{Nil, ErrNotFound, fmt.Errorf("load user: %w", ErrNotFound)} {
+		if !IsNotFound(err) {
+			t.Errorf("expected %v to be not found", err)
+		}
+	}
+	if IsNotFound(nil) || IsNotFound(errors.New("boom")) {
+		t.Error("expected other errors not to be not found")
+	}
+}
@@ -0,0 +1,277 @@
+package rredis
+
+import "time"
+
+// OptionFunc customizes the Option used by New.
+type OptionFunc func(*Option)
+
+// New returns a Redis with given option funcs, it pings the server before returning.
+//
+//	client, err := rredis.New("127.0.0.1:6379", rredis.WithDB(3), rredis.WithPoolSize(20))
+func New(addr string, opts ...OptionFunc) (*Redis, error) {
+	return NewRedis(addr, newOption(opts...))
+}
+
+func newOption(opts ...OptionFunc) *Option {
+	o := &Option{Type: TypeNode}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// WithCluster uses redis cluster, addr is a comma separated list of nodes.
+func WithCluster() OptionFunc {
+	return func(o *Option) {
+		o.Type = TypeCluster
+	}
+}
+
+// WithSentinel uses the master monitored by sentinel, addr is a comma separated list of sentinels.
+func WithSentinel(masterName, sentinelPass string) OptionFunc {
+	return func(o *Option) {
+		o.Type = TypeSentinel
+		o.MasterName = masterName
+		o.SentinelPass = sentinelPass
+	}
+}
+
+// WithDB selects the database, only for node and sentinel.
+func WithDB(db int) OptionFunc {
+	return func(o *Option) {
+		o.DB = db
+	}
+}
+
+// WithPass sets the password.
+func WithPass(pass string) OptionFunc {
+	return func(o *Option) {
+		o.Pass = pass
+	}
+}
+
+// WithUsername sets the ACL user, the password is set by WithPass.
+func WithUsername(username string) OptionFunc {
+	return func(o *Option) {
+		o.Username = username
+	}
+}
+
+// WithClientName executes CLIENT SETNAME for each connection.
+func WithClientName(name string) OptionFunc {
+	return func(o *Option) {
+		o.ClientName = name
+	}
+}
+
+// WithPoolSize sets the maximum number of connections.
+func WithPoolSize(size int) OptionFunc {
+	return func(o *Option) {
+		o.PoolSize = size
+	}
+}
+
+// WithMinIdle sets the minimum number of idle connections, 8 by default, -1 keeps none.
+func WithMinIdle(conns int) OptionFunc {
+	return func(o *Option) {
+		o.MinIdleConns = conns
+	}
+}
+
+// WithMaxIdle sets the maximum number of idle connections.
+func WithMaxIdle(conns int) OptionFunc {
+	return func(o *Option) {
+		o.MaxIdleConns = conns
+	}
+}
+
+// WithPoolTimeout sets how long to wait for a connection when all are busy.
+func WithPoolTimeout(timeout time.Duration) OptionFunc {
+	return func(o *Option) {
+		o.PoolTimeout = timeout
+	}
+}
+
+// WithConnMaxIdleTime sets how long a connection may be idle before it's closed.
+func WithConnMaxIdleTime(d time.Duration) OptionFunc {
+	return func(o *Option) {
+		o.ConnMaxIdleTime = d
+	}
+}
+
+// WithConnMaxLifetime sets how long a connection may be reused.
+func WithConnMaxLifetime(d time.Duration) OptionFunc {
+	return func(o *Option) {
+		o.ConnMaxLifetime = d
+	}
+}
+
+// WithDialTimeout sets the timeout for establishing new connections.
+func WithDialTimeout(timeout time.Duration) OptionFunc {
+	return func(o *Option) {
+		o.DialTimeout = timeout
+	}
+}
+
+// WithReadTimeout sets the timeout for socket reads.
+func WithReadTimeout(timeout time.Duration) OptionFunc {
+	return func(o *Option) {
+		o.ReadTimeout = timeout
+	}
+}
+
+// WithWriteTimeout sets the timeout for socket writes.
+func WithWriteTimeout(timeout time.Duration) OptionFunc {
+	return func(o *Option) {
+		o.WriteTimeout = timeout
+	}
+}
+
+// WithContextTimeout makes the deadlines of the contexts bound socket reads and writes.
+func WithContextTimeout() OptionFunc {
+	return func(o *Option) {
+		o.ContextTimeout = true
+	}
+}
+
+// WithBlockingTimeout sets the timeout of BLPop and BRPop, 5 seconds by default.
+func WithBlockingTimeout(timeout time.Duration) OptionFunc {
+	return func(o *Option) {
+		o.BlockingTimeout = timeout
+	}
+}
+
+// WithMaxRetries sets the maximum number of retries before giving up, -1 disables retries.
+func WithMaxRetries(retries int) OptionFunc {
+	return func(o *Option) {
+		o.MaxRetries = retries
+	}
+}
+
+// WithRetryBackoff sets the minimum and maximum backoff between retries.
+func WithRetryBackoff(min, max time.Duration) OptionFunc {
+	return func(o *Option) {
+		o.MinRetryBackoff = min
+		o.MaxRetryBackoff = max
+	}
+}
+
+// WithTls enables tls, the server certificate is verified with the system roots.
+func WithTls() OptionFunc {
+	return func(o *Option) {
+		o.Tls = true
+	}
+}
+
+// WithTLSCA enables tls and verifies the server with the given PEM bundle.
+func WithTLSCA(caFile string) OptionFunc {
+	return func(o *Option) {
+		o.Tls = true
+		o.TLSCAFile = caFile
+	}
+}
+
+// WithTLSCert enables tls with a client certificate for mutual TLS.
+func WithTLSCert(certFile, keyFile string) OptionFunc {
+	return func(o *Option) {
+		o.Tls = true
+		o.TLSCertFile = certFile
+		o.TLSKeyFile = keyFile
+	}
+}
+
+// WithTLSServerName sets the server name used to verify the server certificate.
+func WithTLSServerName(name string) OptionFunc {
+	return func(o *Option) {
+		o.TLSServerName = name
+	}
+}
+
+// WithTLSMinVersion sets the minimum tls version, like tls.VersionTLS13.
+func WithTLSMinVersion(version uint16) OptionFunc {
+	return func(o *Option) {
+		o.TLSMinVersion = version
+	}
+}
+
+// WithTLSInsecureSkipVerify disables the verification of the server certificate.
+func WithTLSInsecureSkipVerify() OptionFunc {
+	return func(o *Option) {
+		o.TLSInsecureSkipVerify = true
+	}
+}
+
+// WithLogger sets the logger of slow commands.
+func WithLogger(logger Logger) OptionFunc {
+	return func(o *Option) {
+		o.Logger = logger
+	}
+}
+
+// WithSlowThreshold logs commands slower than threshold, -1 disables slow logging.
+func WithSlowThreshold(threshold time.Duration) OptionFunc {
+	return func(o *Option) {
+		o.SlowThreshold = threshold
+	}
+}
+
+// WithLogValues logs the values of slow commands, they are redacted by default.
+func WithLogValues() OptionFunc {
+	return func(o *Option) {
+		o.LogValues = true
+	}
+}
+
+// WithMetrics records the commands and the pool stats with recorder.
+func WithMetrics(recorder MetricsRecorder) OptionFunc {
+	return func(o *Option) {
+		o.Metrics = recorder
+	}
+}
+
+// WithMetricsInterval sets how often the pool stats are recorded.
+func WithMetricsInterval(interval time.Duration) OptionFunc {
+	return func(o *Option) {
+		o.MetricsInterval = interval
+	}
+}
+
+// WithTracer starts a span for each command and pipeline with tracer.
+func WithTracer(tracer Tracer) OptionFunc {
+	return func(o *Option) {
+		o.Tracer = tracer
+	}
+}
+
+// WithKeyPrefix prepends prefix to every key.
+func WithKeyPrefix(prefix string) OptionFunc {
+	return func(o *Option) {
+		o.KeyPrefix = prefix
+	}
+}
+
+// WithTracking enables server-assisted client side caching of Get, MGet and HGetAll,
+// keeping up to size keys in memory for expire, see Option.TrackingSize.
+func WithTracking(size int, expire time.Duration) OptionFunc {
+	return func(o *Option) {
+		o.TrackingSize = size
+		o.TrackingExpire = expire
+	}
+}
+
+// WithTrackingBroadcast tracks every key starting with prefixes, instead of the keys read.
+// Without prefixes, all the keys are tracked.
+func WithTrackingBroadcast(prefixes ...string) OptionFunc {
+	return func(o *Option) {
+		o.TrackingBroadcast = true
+		o.TrackingPrefixes = prefixes
+	}
+}
+
+// WithStartupTimeout makes New retry the ping with backoff for timeout before giving up.
+func WithStartupTimeout(timeout time.Duration) OptionFunc {
+	return func(o *Option) {
+		o.StartupTimeout = timeout
+	}
+}
@@ -9,6 +9,7 @@ func (s *Redis) Pipelined(fn func(Pipeliner) error) error {
 
 // PipelinedCtx lets fn execute pipelined commands.
 // Results need to be retrieved by calling Pipeline.Exec()
+// Keys are not prefixed in pipelines, use Key to prefix them with the namespace.
 func (s *Redis) PipelinedCtx(ctx context.Context, fn func(Pipeliner) error) error {
 	_, err := s.client.Pipelined(ctx, fn)
 	return err
@@ -0,0 +1,237 @@
+package rredis
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultBuckets are the default latency buckets in seconds of PrometheusRecorder.
+var DefaultBuckets = []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1}
+
+type (
+	// PrometheusRecorder is a MetricsRecorder that serves the recorded metrics
+	// in the prometheus text exposition format.
+	//
+	//	recorder := rredis.NewPrometheusRecorder("app")
+	//	client, err := rredis.New(addr, rredis.WithMetrics(recorder))
+	//	http.Handle("/metrics/redis", recorder)
+	PrometheusRecorder struct {
+		namespace string
+		buckets   []float64
+
+		lock      sync.Mutex
+		calls     map[callKey]uint64
+		durations map[string]*histogram
+		pool      PoolStats
+		cache     map[cacheKey]uint64
+		loads     map[string]*histogram
+	}
+
+	callKey struct {
+		cmd    string
+		status string
+	}
+
+	cacheKey struct {
+		namespace string
+		event     string
+	}
+
+	histogram struct {
+		counts []uint64
+		count  uint64
+		sum    float64
+	}
+)
+
+// NewPrometheusRecorder returns a PrometheusRecorder, metric names are prefixed with namespace.
+// The latency buckets default to DefaultBuckets.
+func NewPrometheusRecorder(namespace string, buckets ...float64) *PrometheusRecorder {
+	if len(buckets) == 0 {
+		buckets = DefaultBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	if len(namespace) > 0 {
+		namespace += "_"
+	}
+
+	return &PrometheusRecorder{
+		namespace: namespace,
+		buckets:   buckets,
+		calls:     make(map[callKey]uint64),
+		durations: make(map[string]*histogram),
+		cache:     make(map[cacheKey]uint64),
+		loads:     make(map[string]*histogram),
+	}
+}
+
+// ObserveCommand implements MetricsRecorder.
+func (p *PrometheusRecorder) ObserveCommand(cmd, status string, duration time.Duration) {
+	seconds := duration.Seconds()
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.calls[callKey{cmd: cmd, status: status}]++
+	p.observe(p.durations, cmd, seconds)
+}
+
+// ObserveCache implements CacheMetricsRecorder.
+func (p *PrometheusRecorder) ObserveCache(namespace, event string, count int, duration time.Duration) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.cache[cacheKey{namespace: namespace, event: event}] += uint64(count)
+	if event == CacheLoad || event == CacheLoadError {
+		p.observe(p.loads, namespace, duration.Seconds())
+	}
+}
+
+// observe adds seconds to the histogram of label in histograms.
+func (p *PrometheusRecorder) observe(histograms map[string]*histogram, label string, seconds float64) {
+	h, ok := histograms[label]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(p.buckets))}
+		histograms[label] = h
+	}
+	for i, bound := range p.buckets {
+		if seconds <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += seconds
+}
+
+// ObservePoolStats implements MetricsRecorder.
+func (p *PrometheusRecorder) ObservePoolStats(stats *PoolStats) {
+	p.lock.Lock()
+	p.pool = *stats
+	p.lock.Unlock()
+}
+
+// ServeHTTP writes the metrics in the prometheus text exposition format.
+func (p *PrometheusRecorder) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	p.WriteTo(w)
+}
+
+// WriteTo writes the metrics in the prometheus text exposition format to w.
+func (p *PrometheusRecorder) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+
+	p.lock.Lock()
+	p.writeCalls(&b)
+	p.writeDurations(&b)
+	p.writePool(&b)
+	p.writeCache(&b)
+	p.lock.Unlock()
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func (p *PrometheusRecorder) writeCalls(b *strings.Builder) {
+	keys := make([]callKey, 0, len(p.calls))
+	for k := range p.calls {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].cmd != keys[j].cmd {
+			return keys[i].cmd < keys[j].cmd
+		}
+		return keys[i].status < keys[j].status
+	})
+
+	name := p.namespace + "redis_commands_total"
+	writeHeader(b, name, "counter", "Total number of redis commands by status.")
+	for _, k := range keys {
+		fmt.Fprintf(b, "%s{cmd=%q,status=%q} %d\n", name, k.cmd, k.status, p.calls[k])
+	}
+}
+
+func (p *PrometheusRecorder) writeDurations(b *strings.Builder) {
+	name := p.namespace + "redis_command_duration_seconds"
+	writeHeader(b, name, "histogram", "Latency of redis commands in seconds.")
+	p.writeHistograms(b, name, "cmd", p.durations)
+}
+
+func (p *PrometheusRecorder) writeCache(b *strings.Builder) {
+	keys := make([]cacheKey, 0, len(p.cache))
+	for k := range p.cache {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].namespace != keys[j].namespace {
+			return keys[i].namespace < keys[j].namespace
+		}
+		return keys[i].event < keys[j].event
+	})
+
+	name := p.namespace + "redis_cache_events_total"
+	writeHeader(b, name, "counter", "Total number of cache events by namespace.")
+	for _, k := range keys {
+		fmt.Fprintf(b, "%s{namespace=%q,event=%q} %d\n", name, k.namespace, k.event, p.cache[k])
+	}
+
+	name = p.namespace + "redis_cache_load_duration_seconds"
+	writeHeader(b, name, "histogram", "Latency of cache loaders in seconds.")
+	p.writeHistograms(b, name, "namespace", p.loads)
+}
+
+func (p *PrometheusRecorder) writeHistograms(b *strings.Builder, name, label string,
+	histograms map[string]*histogram) {
+	values := make([]string, 0, len(histograms))
+	for value := range histograms {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+
+	for _, value := range values {
+		h := histograms[value]
+		for i, bound := range p.buckets {
+			fmt.Fprintf(b, "%s_bucket{%s=%q,le=%q} %d\n", name, label, value, formatFloat(bound), h.counts[i])
+		}
+		fmt.Fprintf(b, "%s_bucket{%s=%q,le=\"+Inf\"} %d\n", name, label, value, h.count)
+		fmt.Fprintf(b, "%s_sum{%s=%q} %s\n", name, label, value, formatFloat(h.sum))
+		fmt.Fprintf(b, "%s_count{%s=%q} %d\n", name, label, value, h.count)
+	}
+}
+
+func (p *PrometheusRecorder) writePool(b *strings.Builder) {
+	metrics := []struct {
+		name  string
+		kind  string
+		help  string
+		value uint32
+	}{
+		{"redis_pool_hits_total", "counter", "Number of times a free connection was found in the pool.", p.pool.Hits},
+		{"redis_pool_misses_total", "counter", "Number of times a free connection was not found in the pool.", p.pool.Misses},
+		{"redis_pool_timeouts_total", "counter", "Number of times a wait timeout occurred.", p.pool.Timeouts},
+		{"redis_pool_total_conns", "gauge", "Number of total connections in the pool.", p.pool.TotalConns},
+		{"redis_pool_idle_conns", "gauge", "Number of idle connections in the pool.", p.pool.IdleConns},
+		{"redis_pool_stale_conns_total", "counter", "Number of stale connections removed from the pool.", p.pool.StaleConns},
+	}
+
+	for _, m := range metrics {
+		name := p.namespace + m.name
+		writeHeader(b, name, m.kind, m.help)
+		fmt.Fprintf(b, "%s %d\n", name, m.value)
+	}
+}
+
+func writeHeader(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
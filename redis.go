@@ -2,7 +2,6 @@ package rredis
 
 import (
 	"context"
-	"crypto/tls"
 	"errors"
 	"fmt"
 	red "github.com/redis/go-redis/v9"
@@ -16,6 +15,8 @@ const (
 	TypeCluster RType = "cluster"
 	// TypeNode means redis node.
 	TypeNode RType = "node"
+	// TypeSentinel means redis master/replicas behind sentinel.
+	TypeSentinel RType = "sentinel"
 	// Nil is an alias of redis.Nil.
 	Nil = red.Nil
 
@@ -23,6 +24,9 @@ const (
 	readWriteTimeout     = 2 * time.Second
 	defaultSlowThreshold = time.Millisecond * 100
 
+	minStartupBackoff = 100 * time.Millisecond
+	maxStartupBackoff = 2 * time.Second
+
 	defDatabase = 0
 	maxRetries  = 3
 	idleConns   = 8
@@ -31,6 +35,11 @@ const (
 var (
 	// ErrNilNode is an error that indicates a nil redis node.
 	ErrNilNode = errors.New("nil redis node")
+	// ErrNotFound is returned by cache loaders when the value doesn't exist,
+	// the cache helpers remember it as a tombstone so that the loader isn't called again.
+	ErrNotFound = errors.New("not found")
+	// ErrPing is an error that indicates the server didn't reply to ping, the cause is wrapped with it.
+	ErrPing = errors.New("error Ping")
 )
 
 type (
@@ -47,17 +56,105 @@ type (
 	}
 
 	Option struct {
-		isCluster bool
+		isCluster  bool
+		isSentinel bool
 		//
 		Type RType
 		Pass string
 		DB   int
 		Tls  bool
+
+		// TLS settings, used when Tls is true.
+		// TLSCAFile is a PEM bundle used to verify the server instead of the system roots,
+		// TLSCertFile and TLSKeyFile are the client certificate for mutual TLS.
+		TLSCAFile             string
+		TLSCertFile           string
+		TLSKeyFile            string
+		TLSServerName         string
+		TLSMinVersion         uint16
+		TLSInsecureSkipVerify bool
+
+		// MasterName is the name of the master monitored by sentinel, used with TypeSentinel.
+		MasterName string
+		// SentinelPass is the password of the sentinel nodes, used with TypeSentinel.
+		SentinelPass string
+
+		// Username is the ACL user, Pass is used as its password when set.
+		Username   string
+		ClientName string
+
+		// Pool and timeout settings, zero values use the defaults,
+		// read and write timeouts default to 2 seconds, -1 disables them.
+		// MaxRetries defaults to 3 and MinIdleConns to 8, -1 disables them.
+		MaxRetries      int
+		MinRetryBackoff time.Duration
+		MaxRetryBackoff time.Duration
+		DialTimeout     time.Duration
+		ReadTimeout     time.Duration
+		WriteTimeout    time.Duration
+		PoolSize        int
+		PoolTimeout     time.Duration
+		MinIdleConns    int
+		MaxIdleConns    int
+		ConnMaxIdleTime time.Duration
+		ConnMaxLifetime time.Duration
+
+		// ContextTimeout makes the deadlines of the contexts bound socket reads and writes,
+		// by default only ReadTimeout and WriteTimeout do. The nodes of a Redlock need it
+		// for WithNodeTimeout to cut off a node that accepted the connection but hangs.
+		ContextTimeout bool
+
+		// Logger logs commands slower than SlowThreshold, defaults to the standard logger.
+		// SlowThreshold defaults to 100ms, -1 disables slow logging.
+		// Only command names and keys are logged unless LogValues is true,
+		// blocking commands like BLPOP aren't logged.
+		Logger        Logger
+		SlowThreshold time.Duration
+		LogValues     bool
+
+		// Metrics records the commands, and the pool stats every MetricsInterval, 10 seconds by default.
+		Metrics         MetricsRecorder
+		MetricsInterval time.Duration
+
+		// Tracer starts a span for each command and pipeline.
+		Tracer Tracer
+
+		// KeyPrefix is prepended to every key, see Redis.WithNamespace.
+		KeyPrefix string
+
+		// BlockingTimeout is the timeout of BLPop and BRPop, 5 seconds by default.
+		BlockingTimeout time.Duration
+
+		// StartupTimeout makes NewRedis retry the ping with backoff for this long,
+		// by default it pings only once.
+		StartupTimeout time.Duration
+
+		// TrackingSize enables server-assisted client side caching with CLIENT TRACKING,
+		// the results of Get, MGet and HGetAll are kept in memory for up to TrackingSize keys
+		// and TrackingExpire, one minute by default, until the server invalidates them.
+		// TrackingBroadcast tracks every key starting with TrackingPrefixes, instead of the keys read.
+		// It requires redis 6 and isn't supported with TypeCluster.
+		TrackingSize      int
+		TrackingExpire    time.Duration
+		TrackingBroadcast bool
+		TrackingPrefixes  []string
 	}
 
 	Redis struct {
 		client red.UniversalClient
 		ctx    context.Context
+		// prefix is prepended to every key, see WithNamespace.
+		prefix string
+		// stop stops the background goroutines of the client.
+		stop context.CancelFunc
+		// flight shares the loading of missing cache keys.
+		flight *singleFlight
+		// tracker caches reads invalidated by the server, see Option.TrackingSize.
+		tracker *tracker
+		// stats counts the cache events by namespace.
+		stats *cacheStats
+		// blockingTimeout is the timeout of BLPop and BRPop.
+		blockingTimeout time.Duration
 	}
 
 	// RedisNode interface represents a redis node.
@@ -88,51 +185,203 @@ type (
 	StringCmd = red.StringCmd
 )
 
+// NewClient returns a Redis with given options.
+// With TypeCluster and TypeSentinel, addr is a comma separated list of
+// cluster nodes or sentinel nodes.
+// NewClient panics if the tls files in opt can't be loaded, or with ErrTrackingCluster
+// if tracking is enabled with TypeCluster, use NewRedis to get an error instead.
 func NewClient(addr string, opt *Option) *Redis {
+	r, err := newClient(addr, opt)
+	if err != nil {
+		panic(err)
+	}
+
+	return r
+}
+
+// FromClient returns a Redis that wraps an existing go-redis client,
+// like one built with custom dialers or hooks, or connected to miniredis in tests.
+// Closing the returned Redis closes client.
+func FromClient(client red.UniversalClient) *Redis {
+	return newRedis(client, "")
+}
+
+func newRedis(client red.UniversalClient, prefix string) *Redis {
+	return &Redis{
+		client: client,
+		ctx:    context.Background(),
+		prefix: prefix,
+		flight: newSingleFlight(),
+		stats:  newCacheStats(),
+
+		blockingTimeout: blockingQueryTimeout,
+	}
+}
+
+func newClient(addr string, opt *Option) (*Redis, error) {
 	rdc := loadOption(opt)
-	r := new(Redis)
+	options := newUniversalOptions(addr, rdc)
+
+	tlsConfig, err := newTLSConfig(rdc)
+	if err != nil {
+		return nil, err
+	}
+	options.TLSConfig = tlsConfig
 
+	if rdc.TrackingSize > 0 && rdc.isCluster {
+		return nil, ErrTrackingCluster
+	}
+
+	var client red.UniversalClient
 	if rdc.isCluster {
-		var tlsConfig *tls.Config
-		if rdc.Tls {
-			tlsConfig = &tls.Config{
-				InsecureSkipVerify: true,
+		client = red.NewClusterClient(options.Cluster())
+	} else if rdc.isSentinel {
+		client = red.NewFailoverClient(options.Failover())
+	} else {
+		client = red.NewClient(options.Simple())
+	}
+	addHooks(client, rdc)
+
+	r := newRedis(client, rdc.KeyPrefix)
+	r.blockingTimeout = rdc.BlockingTimeout
+	if rdc.TrackingSize > 0 {
+		r.tracker = newTracker(client, rdc, func(onConnect func(ctx context.Context, cn *red.Conn) error) *red.Client {
+			// invalidations are redirected as pub/sub messages only to RESP2 connections.
+			var c *red.Client
+			if rdc.isSentinel {
+				o := options.Failover()
+				o.OnConnect = onConnect
+				o.Protocol = 2
+				c = red.NewFailoverClient(o)
+			} else {
+				o := options.Simple()
+				o.OnConnect = onConnect
+				o.Protocol = 2
+				c = red.NewClient(o)
 			}
-		}
+			addHooks(c, rdc)
+			return c
+		})
+	}
 
-		options := &red.ClusterOptions{
-			Addrs:        splitClusterAddr(addr),
-			Password:     rdc.Pass,
-			TLSConfig:    tlsConfig,
-			MaxRetries:   maxRetries,
-			MinIdleConns: idleConns,
+	if rdc.Metrics != nil {
+		if recorder, ok := rdc.Metrics.(CacheMetricsRecorder); ok {
+			r.stats.recorder = recorder
 		}
 
-		client := red.NewClusterClient(options)
-		r = &Redis{client: client, ctx: context.Background()}
-	} else {
-		options := &red.Options{
-			Addr:         addr,
-			Password:     rdc.Pass,
-			DB:           rdc.DB,
-			MaxRetries:   maxRetries,
-			MinIdleConns: idleConns,
-		}
-		client := red.NewClient(options)
-		r = &Redis{client: client, ctx: context.Background()}
+		var ctx context.Context
+		ctx, r.stop = context.WithCancel(context.Background())
+		go reportPoolStats(ctx, client, rdc.Metrics, rdc.MetricsInterval)
 	}
 
-	return r
+	return r, nil
+}
+
+// addHooks adds the logging, tracing and metrics hooks configured in rdc to client.
+func addHooks(client red.UniversalClient, rdc *Option) {
+	if rdc.SlowThreshold >= 0 {
+		client.AddHook(newSlowLogHook(rdc))
+	}
+
+	if rdc.Tracer != nil {
+		installTracing(client, rdc.Tracer)
+	}
+
+	if rdc.Metrics != nil {
+		client.AddHook(metricsHook{recorder: rdc.Metrics})
+	}
+}
+
+func newUniversalOptions(addr string, rdc *Option) *red.UniversalOptions {
+	options := &red.UniversalOptions{
+		Addrs:            []string{addr},
+		ClientName:       rdc.ClientName,
+		DB:               rdc.DB,
+		Username:         rdc.Username,
+		Password:         rdc.Pass,
+		SentinelPassword: rdc.SentinelPass,
+		MasterName:       rdc.MasterName,
+		MaxRetries:       rdc.MaxRetries,
+		MinRetryBackoff:  rdc.MinRetryBackoff,
+		MaxRetryBackoff:  rdc.MaxRetryBackoff,
+		DialTimeout:      rdc.DialTimeout,
+		ReadTimeout:      rdc.ReadTimeout,
+		WriteTimeout:     rdc.WriteTimeout,
+		PoolSize:         rdc.PoolSize,
+		PoolTimeout:      rdc.PoolTimeout,
+		MinIdleConns:     rdc.MinIdleConns,
+		MaxIdleConns:     rdc.MaxIdleConns,
+		ConnMaxIdleTime:  rdc.ConnMaxIdleTime,
+		ConnMaxLifetime:  rdc.ConnMaxLifetime,
+
+		ContextTimeoutEnabled: rdc.ContextTimeout,
+	}
+	if rdc.isCluster || rdc.isSentinel {
+		options.Addrs = splitClusterAddr(addr)
+	}
+
+	return options
 }
 
 func NewRedis(addr string, opt *Option) (*Redis, error) {
-	r := NewClient(addr, opt)
-	if !r.Ping() {
-		return nil, errors.New("error Ping")
+	r, err := newClient(addr, opt)
+	if err != nil {
+		return nil, err
+	}
+	if err = r.waitForServer(r.ctx, loadOption(opt).StartupTimeout); err != nil {
+		r.Close()
+		return nil, err
 	}
 	return r, nil
 }
 
+// waitForServer pings the server, retrying with backoff until timeout passes.
+func (s *Redis) waitForServer(ctx context.Context, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	backoff := minStartupBackoff
+	for {
+		err := s.PingErr(ctx)
+		if err == nil {
+			return nil
+		}
+
+		wait := time.Until(deadline)
+		if wait <= 0 {
+			return pingError{err: err}
+		}
+		if wait > backoff {
+			wait = backoff
+		}
+
+		select {
+		case <-ctx.Done():
+			return pingError{err: err}
+		case <-time.After(wait):
+		}
+
+		if backoff *= 2; backoff > maxStartupBackoff {
+			backoff = maxStartupBackoff
+		}
+	}
+}
+
+// pingError wraps the cause of a failed ping, it matches ErrPing with errors.Is.
+type pingError struct {
+	err error
+}
+
+func (e pingError) Error() string {
+	return ErrPing.Error() + ": " + e.err.Error()
+}
+
+func (e pingError) Unwrap() error {
+	return e.err
+}
+
+func (e pingError) Is(target error) bool {
+	return target == ErrPing
+}
+
 // MustNewRedis returns a Redis with given options.
 func MustNewRedis(addr string, opts *Option) *Redis {
 	rds, err := NewRedis(addr, opts)
@@ -144,34 +393,19 @@ func MustNewRedis(addr string, opts *Option) *Redis {
 	return rds
 }
 
-//func newRedis(addr string, opt *Option) *Option {
-// Initial configuration
-//r := &Redis{
-//	Addr: addr,
-//	Type: TypeNode,
-//	DB:   defDatabase,
-//	Pass:  "",
-//}
-//
-//// load user configuration
-//for _, opt := range opts {
-//	opt(r)
-//}
-//
-//// is cluster mode
-//r.isCluster = r.Type == TypeCluster
-//
-//return r
-
-//}
-
 func loadOption(opt *Option) *Option {
 	o := &Option{
-		Type:      TypeNode,
-		DB:        defDatabase,
-		isCluster: false,
-		Pass:      "",
-		Tls:       false,
+		Type:            TypeNode,
+		DB:              defDatabase,
+		isCluster:       false,
+		Pass:            "",
+		Tls:             false,
+		MaxRetries:      maxRetries,
+		MinIdleConns:    idleConns,
+		ReadTimeout:     readWriteTimeout,
+		WriteTimeout:    readWriteTimeout,
+		MetricsInterval: defaultStatsInterval,
+		BlockingTimeout: blockingQueryTimeout,
 	}
 
 	if opt == nil {
@@ -183,8 +417,65 @@ func loadOption(opt *Option) *Option {
 	if len(opt.Pass) > 0 {
 		o.Pass = opt.Pass
 	}
-	o.isCluster = opt.Type == TypeCluster
+	if len(opt.Type) > 0 {
+		o.Type = opt.Type
+	}
+	o.isCluster = o.Type == TypeCluster
+	o.isSentinel = o.Type == TypeSentinel
 	o.Tls = opt.Tls
+	o.TLSCAFile = opt.TLSCAFile
+	o.TLSCertFile = opt.TLSCertFile
+	o.TLSKeyFile = opt.TLSKeyFile
+	o.TLSServerName = opt.TLSServerName
+	o.TLSMinVersion = opt.TLSMinVersion
+	o.TLSInsecureSkipVerify = opt.TLSInsecureSkipVerify
+	o.MasterName = opt.MasterName
+	o.SentinelPass = opt.SentinelPass
+	o.Username = opt.Username
+	o.ClientName = opt.ClientName
+	if opt.MaxRetries != 0 {
+		o.MaxRetries = opt.MaxRetries
+	}
+	if opt.MinIdleConns > 0 {
+		o.MinIdleConns = opt.MinIdleConns
+	} else if opt.MinIdleConns < 0 {
+		o.MinIdleConns = 0
+	}
+	o.MinRetryBackoff = opt.MinRetryBackoff
+	o.MaxRetryBackoff = opt.MaxRetryBackoff
+	o.DialTimeout = opt.DialTimeout
+	if opt.ReadTimeout != 0 {
+		o.ReadTimeout = opt.ReadTimeout
+	}
+	if opt.WriteTimeout != 0 {
+		o.WriteTimeout = opt.WriteTimeout
+	}
+	o.PoolSize = opt.PoolSize
+	o.PoolTimeout = opt.PoolTimeout
+	o.MaxIdleConns = opt.MaxIdleConns
+	o.ConnMaxIdleTime = opt.ConnMaxIdleTime
+	o.ConnMaxLifetime = opt.ConnMaxLifetime
+	o.ContextTimeout = opt.ContextTimeout
+	o.Logger = opt.Logger
+	o.SlowThreshold = opt.SlowThreshold
+	o.LogValues = opt.LogValues
+	o.Metrics = opt.Metrics
+	o.Tracer = opt.Tracer
+	o.KeyPrefix = opt.KeyPrefix
+	o.StartupTimeout = opt.StartupTimeout
+	if opt.BlockingTimeout > 0 {
+		o.BlockingTimeout = opt.BlockingTimeout
+	}
+	o.TrackingSize = opt.TrackingSize
+	o.TrackingExpire = opt.TrackingExpire
+	if o.TrackingExpire <= 0 {
+		o.TrackingExpire = defaultTrackingExpire
+	}
+	o.TrackingBroadcast = opt.TrackingBroadcast
+	o.TrackingPrefixes = opt.TrackingPrefixes
+	if opt.MetricsInterval > 0 {
+		o.MetricsInterval = opt.MetricsInterval
+	}
 
 	return o
 }
@@ -193,12 +484,24 @@ func loadOption(opt *Option) *Option {
 
 // Close
 func (s *Redis) Close() error {
+	if s.stop != nil {
+		s.stop()
+	}
+	if s.tracker != nil {
+		s.tracker.close()
+	}
 	if s.client != nil {
 		return s.client.Close()
 	}
 	return nil
 }
 
+// Client returns the underlying go-redis client.
+// Keys of commands sent with it are not prefixed with the namespace of s.
+func (s *Redis) Client() red.UniversalClient {
+	return s.client
+}
+
 // Ping is the implementation of redis ping command.
 func (s *Redis) Ping() bool {
 	return s.PingCtx(s.ctx)
@@ -206,13 +509,20 @@ func (s *Redis) Ping() bool {
 
 // PingCtx is the implementation of redis ping command.
 func (s *Redis) PingCtx(ctx context.Context) (val bool) {
+	return s.PingErr(ctx) == nil
+}
+
+// PingErr is the implementation of redis ping command, it returns why the ping failed.
+func (s *Redis) PingErr(ctx context.Context) error {
 	v, err := s.client.Ping(ctx).Result()
 	if err != nil {
-		val = false
+		return err
+	}
+	if v != "PONG" {
+		return fmt.Errorf("unexpected ping reply: %s", v)
 	}
 
-	val = v == "PONG"
-	return
+	return nil
 }
 
 // ------------------------
@@ -1,7 +1,10 @@
 package rredis
 
 import (
+	"errors"
+	"net"
 	"testing"
+	"time"
 )
 
 func TestNewRedis(t *testing.T) {
@@ -28,3 +31,37 @@ func TestNewRedis(t *testing.T) {
 	//})
 
 }
+
+func TestNewRedisStartupTimeout(t *testing.T) {
+	start := time.Now()
+	_, err := New("127.0.0.1:1", WithStartupTimeout(300*time.Millisecond), WithMaxRetries(-1))
+	if !errors.Is(err, ErrPing) {
+		t.Fatalf("expected ErrPing, got %v", err)
+	}
+
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		t.Errorf("expected the dial error to be wrapped, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Errorf("expected retries for 300ms, gave up after %s", elapsed)
+	}
+}
+
+func TestBlockingTimeout(t *testing.T) {
+	if rds := FromClient(nil); rds.blockingTimeout != 5*time.Second {
+		t.Fatalf("expected the default blocking timeout, got %s", rds.blockingTimeout)
+	}
+
+	rds, err := newClient("127.0.0.1:1", newOption(WithBlockingTimeout(time.Minute)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rds.Close()
+	if rds.blockingTimeout != time.Minute {
+		t.Fatalf("expected the blocking timeout set, got %s", rds.blockingTimeout)
+	}
+	if ns := rds.WithNamespace("app:"); ns.blockingTimeout != time.Minute {
+		t.Fatalf("expected the namespace to keep the blocking timeout, got %s", ns.blockingTimeout)
+	}
+}
@@ -0,0 +1,246 @@
+package rredis
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	// clockDriftFactor is the share of the expiration allowed for clock drift between the nodes.
+	clockDriftFactor = 0.01
+	// minClockDrift accounts for the precision of redis expirations.
+	minClockDrift = 2 * time.Millisecond
+	// maxNodeTimeout bounds the time spent on each node by an attempt, so that a node down
+	// doesn't eat the validity of the lock, see WithNodeTimeout.
+	maxNodeTimeout = 50 * time.Millisecond
+)
+
+// Redlock is a distributed lock over independent redis nodes, with the Redlock algorithm:
+// it's held if it's acquired on a majority of the nodes within its expiration,
+// less the time taken and the clock drift. It survives the failure of a minority of the nodes.
+// Unlike Mutex, it has no fencing token, as the counters of the nodes can't be ordered.
+//
+//	lock := rredis.NewRedlock([]*rredis.Redis{node1, node2, node3}, "jobs:report", rredis.WithWatchdog())
+//	if err := lock.Lock(ctx); err != nil {
+//		return err
+//	}
+//	defer lock.Unlock(context.Background())
+type Redlock struct {
+	nodes  []*Mutex
+	opts   *lockOptions
+	quorum int
+
+	lock       sync.Mutex
+	token      string
+	validUntil time.Time
+	watchdog   watchdog
+}
+
+// NewRedlock returns a Redlock on key over nodes, which must be independent masters,
+// created WithContextTimeout so that a hung node doesn't outlast WithNodeTimeout.
+func NewRedlock(nodes []*Redis, key string, opts ...LockOption) *Redlock {
+	o := newLockOptions(opts...)
+	mutexes := make([]*Mutex, len(nodes))
+	for i, node := range nodes {
+		mutexes[i] = &Mutex{
+			rds:  node,
+			key:  key,
+			opts: o,
+		}
+	}
+
+	return &Redlock{
+		nodes:  mutexes,
+		opts:   o,
+		quorum: len(nodes)/2 + 1,
+	}
+}
+
+// TryLock acquires the lock on a majority of the nodes, without waiting.
+// It returns an error only if too many nodes failed for a majority to be acquired,
+// so that Lock keeps waiting while a minority of the nodes is down.
+func (l *Redlock) TryLock(ctx context.Context) (bool, error) {
+	token := randomToken()
+	start := time.Now()
+	acquired, failed, err := l.each(ctx, l.nodeTimeout(), func(ctx context.Context, m *Mutex) error {
+		fence, err := m.acquire(ctx, token)
+		if err == nil && fence == 0 {
+			return ErrLockNotHeld
+		}
+		return err
+	})
+
+	validity := l.opts.expire - time.Since(start) - l.drift()
+	if acquired < l.quorum || validity <= 0 {
+		// the attempt may have succeeded on nodes that timed out, release them all
+		// in the background, with the budget of a release instead of an attempt.
+		go l.releaseAll(token)
+		if l.unreachable(failed) {
+			return false, err
+		}
+		return false, nil
+	}
+
+	l.lock.Lock()
+	l.token = token
+	l.validUntil = start.Add(validity)
+	l.lock.Unlock()
+
+	if l.opts.watchdog {
+		l.watchdog.start(context.Background(), l.opts.expire/3, l.Extend)
+	}
+
+	return true, nil
+}
+
+// Lock acquires the lock on a majority of the nodes, retrying with backoff until it succeeds or ctx is done.
+func (l *Redlock) Lock(ctx context.Context) error {
+	return retryLock(ctx, l.opts, l.TryLock)
+}
+
+// Unlock releases the lock on all the nodes,
+// it returns ErrLockNotHeld if it wasn't held on a majority of them anymore.
+func (l *Redlock) Unlock(ctx context.Context) error {
+	l.watchdog.stopAndWait()
+
+	l.lock.Lock()
+	token := l.token
+	l.token = ""
+	l.validUntil = time.Time{}
+	l.lock.Unlock()
+
+	if len(token) == 0 {
+		return ErrLockNotHeld
+	}
+
+	released, failed, err := l.each(ctx, l.opts.expire, func(ctx context.Context, m *Mutex) error {
+		return m.release(ctx, token)
+	})
+	if released >= l.quorum {
+		return nil
+	}
+	if l.unreachable(failed) {
+		return err
+	}
+
+	return ErrLockNotHeld
+}
+
+// Extend resets the expiration of the lock on all the nodes, it returns ErrLockNotHeld
+// if it's not held on a majority of them anymore, and the lock is no longer held.
+func (l *Redlock) Extend(ctx context.Context) error {
+	l.lock.Lock()
+	token := l.token
+	l.lock.Unlock()
+
+	if len(token) == 0 {
+		return ErrLockNotHeld
+	}
+
+	start := time.Now()
+	extended, failed, err := l.each(ctx, l.nodeTimeout(), func(ctx context.Context, m *Mutex) error {
+		return m.extend(ctx, token)
+	})
+
+	validity := l.opts.expire - time.Since(start) - l.drift()
+	if extended < l.quorum || validity <= 0 {
+		if l.unreachable(failed) {
+			return err
+		}
+
+		l.lock.Lock()
+		if l.token == token {
+			l.token = ""
+			l.validUntil = time.Time{}
+		}
+		l.lock.Unlock()
+		// the minority of the nodes still holding it is released early.
+		go l.releaseAll(token)
+		return ErrLockNotHeld
+	}
+
+	l.lock.Lock()
+	if l.token == token {
+		l.validUntil = start.Add(validity)
+	}
+	l.lock.Unlock()
+
+	return nil
+}
+
+// Validity returns how long the lock is still held for sure, 0 if it's not held.
+func (l *Redlock) Validity() time.Duration {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if validity := time.Until(l.validUntil); validity > 0 {
+		return validity
+	}
+
+	return 0
+}
+
+// each calls fn on all the nodes concurrently, each with its own timeout, it returns the number
+// of successful calls, of failed calls, and the last error of the failed calls.
+// Calls returning ErrLockNotHeld are neither successful nor failed.
+func (l *Redlock) each(ctx context.Context, timeout time.Duration,
+	fn func(ctx context.Context, m *Mutex) error) (int, int, error) {
+	var lock sync.Mutex
+	var wg sync.WaitGroup
+	var succeeded, failed int
+	var lastErr error
+	for _, node := range l.nodes {
+		wg.Add(1)
+		go func(m *Mutex) {
+			defer wg.Done()
+
+			nodeCtx, cancel := context.WithTimeout(ctx, timeout)
+			err := fn(nodeCtx, m)
+			cancel()
+
+			lock.Lock()
+			if err == nil {
+				succeeded++
+			} else if err != ErrLockNotHeld {
+				failed++
+				lastErr = err
+			}
+			lock.Unlock()
+		}(node)
+	}
+	wg.Wait()
+
+	return succeeded, failed, lastErr
+}
+
+// releaseAll releases token on all the nodes, it's given the whole expiration,
+// after which the nodes release it by themselves.
+func (l *Redlock) releaseAll(token string) {
+	l.each(context.Background(), l.opts.expire, func(ctx context.Context, m *Mutex) error {
+		return m.release(ctx, token)
+	})
+}
+
+// unreachable reports whether failed nodes leave too few nodes for a majority.
+func (l *Redlock) unreachable(failed int) bool {
+	return failed > len(l.nodes)-l.quorum
+}
+
+// nodeTimeout returns the time given to each node by an attempt to acquire or extend the lock.
+func (l *Redlock) nodeTimeout() time.Duration {
+	if l.opts.nodeTimeout > 0 {
+		return l.opts.nodeTimeout
+	}
+
+	timeout := l.opts.expire / 10
+	if timeout > maxNodeTimeout {
+		timeout = maxNodeTimeout
+	}
+
+	return timeout
+}
+
+func (l *Redlock) drift() time.Duration {
+	return time.Duration(float64(l.opts.expire)*clockDriftFactor) + minClockDrift
+}
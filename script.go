@@ -10,7 +10,7 @@ func (s *Redis) Eval(script string, keys []string, args ...interface{}) (interfa
 // EvalCtx is the implementation of redis eval command.
 func (s *Redis) EvalCtx(ctx context.Context, script string, keys []string,
 	args ...interface{}) (val interface{}, err error) {
-	return s.client.Eval(ctx, script, keys, args...).Result()
+	return s.client.Eval(ctx, script, s.keys(keys), args...).Result()
 }
 
 // EvalSha is the implementation of redis evalsha command.
@@ -21,7 +21,7 @@ func (s *Redis) EvalSha(sha string, keys []string, args ...interface{}) (interfa
 // EvalShaCtx is the implementation of redis evalsha command.
 func (s *Redis) EvalShaCtx(ctx context.Context, sha string, keys []string,
 	args ...interface{}) (val interface{}, err error) {
-	return s.client.EvalSha(ctx, sha, keys, args...).Result()
+	return s.client.EvalSha(ctx, sha, s.keys(keys), args...).Result()
 }
 
 // ScriptLoad is the implementation of redis script load command.
@@ -9,7 +9,7 @@ func (s *Redis) SAdd(key string, values ...interface{}) (int64, error) {
 
 // SAddCtx is the implementation of redis sadd command.
 func (s *Redis) SAddCtx(ctx context.Context, key string, values ...interface{}) (val int64, err error) {
-	return s.client.SAdd(ctx, key, values...).Result()
+	return s.client.SAdd(ctx, s.key(key), values...).Result()
 }
 
 // SCard is the implementation of redis scard command.
@@ -19,7 +19,7 @@ func (s *Redis) SCard(key string) (int64, error) {
 
 // SCardCtx is the implementation of redis scard command.
 func (s *Redis) SCardCtx(ctx context.Context, key string) (val int64, err error) {
-	return s.client.SCard(ctx, key).Result()
+	return s.client.SCard(ctx, s.key(key)).Result()
 }
 
 // SScan is the implementation of redis sscan command.
@@ -31,7 +31,7 @@ func (s *Redis) SScan(key string, cursor uint64, match string, count int64) (
 // SScanCtx is the implementation of redis sscan command.
 func (s *Redis) SScanCtx(ctx context.Context, key string, cursor uint64, match string, count int64) (
 	keys []string, cur uint64, err error) {
-	return s.client.SScan(ctx, key, cursor, match, count).Result()
+	return s.client.SScan(ctx, s.key(key), cursor, match, count).Result()
 }
 
 // SDiff is the implementation of redis sdiff command.
@@ -41,7 +41,7 @@ func (s *Redis) SDiff(keys ...string) ([]string, error) {
 
 // SDiffCtx is the implementation of redis sdiff command.
 func (s *Redis) SDiffCtx(ctx context.Context, keys ...string) (val []string, err error) {
-	return s.client.SDiff(ctx, keys...).Result()
+	return s.client.SDiff(ctx, s.keys(keys)...).Result()
 }
 
 // SDiffStore is the implementation of redis sdiffstore command.
@@ -52,7 +52,7 @@ func (s *Redis) SDiffStore(destination string, keys ...string) (int64, error) {
 // SDiffStoreCtx is the implementation of redis sdiffstore command.
 func (s *Redis) SDiffStoreCtx(ctx context.Context, destination string, keys ...string) (
 	val int64, err error) {
-	return s.client.SDiffStore(ctx, destination, keys...).Result()
+	return s.client.SDiffStore(ctx, s.key(destination), s.keys(keys)...).Result()
 }
 
 // SInter is the implementation of redis sinter command.
@@ -62,7 +62,7 @@ func (s *Redis) SInter(keys ...string) ([]string, error) {
 
 // SInterCtx is the implementation of redis sinter command.
 func (s *Redis) SInterCtx(ctx context.Context, keys ...string) (val []string, err error) {
-	return s.client.SInter(ctx, keys...).Result()
+	return s.client.SInter(ctx, s.keys(keys)...).Result()
 }
 
 // SInterStore is the implementation of redis sinterstore command.
@@ -73,7 +73,7 @@ func (s *Redis) SInterStore(destination string, keys ...string) (int64, error) {
 // SInterStoreCtx is the implementation of redis sinterstore command.
 func (s *Redis) SInterStoreCtx(ctx context.Context, destination string, keys ...string) (
 	val int64, err error) {
-	return s.client.SInterStore(ctx, destination, keys...).Result()
+	return s.client.SInterStore(ctx, s.key(destination), s.keys(keys)...).Result()
 }
 
 // SIsMember is the implementation of redis sismember command.
@@ -83,7 +83,7 @@ func (s *Redis) SIsMember(key string, value interface{}) (bool, error) {
 
 // SIsMemberCtx is the implementation of redis sismember command.
 func (s *Redis) SIsMemberCtx(ctx context.Context, key string, value interface{}) (val bool, err error) {
-	return s.client.SIsMember(ctx, key, value).Result()
+	return s.client.SIsMember(ctx, s.key(key), value).Result()
 }
 
 // SMembers is the implementation of redis smembers command.
@@ -93,7 +93,7 @@ func (s *Redis) SMembers(key string) ([]string, error) {
 
 // SMembersCtx is the implementation of redis smembers command.
 func (s *Redis) SMembersCtx(ctx context.Context, key string) (val []string, err error) {
-	return s.client.SMembers(ctx, key).Result()
+	return s.client.SMembers(ctx, s.key(key)).Result()
 }
 
 // SPop is the implementation of redis spop command.
@@ -103,7 +103,7 @@ func (s *Redis) SPop(key string) (string, error) {
 
 // SPopCtx is the implementation of redis spop command.
 func (s *Redis) SPopCtx(ctx context.Context, key string) (val string, err error) {
-	return s.client.SPop(ctx, key).Result()
+	return s.client.SPop(ctx, s.key(key)).Result()
 }
 
 // SRandMember is the implementation of redis srandmember command.
@@ -113,7 +113,7 @@ func (s *Redis) SRandMember(key string) (string, error) {
 
 // SRandMemberCtx is the implementation of redis srandmember command.
 func (s *Redis) SRandMemberCtx(ctx context.Context, key string) (val string, err error) {
-	return s.client.SRandMember(ctx, key).Result()
+	return s.client.SRandMember(ctx, s.key(key)).Result()
 }
 
 // SRandMemberN is the implementation of redis SRandMemberN command.
@@ -123,7 +123,7 @@ func (s *Redis) SRandMemberN(key string, count int64) ([]string, error) {
 
 // SRandMemberNCtx is the implementation of redis SRandMemberN command.
 func (s *Redis) SRandMemberNCtx(ctx context.Context, key string, count int64) (val []string, err error) {
-	return s.client.SRandMemberN(ctx, key, count).Result()
+	return s.client.SRandMemberN(ctx, s.key(key), count).Result()
 }
 
 // SRem is the implementation of redis srem command.
@@ -133,7 +133,7 @@ func (s *Redis) SRem(key string, values ...interface{}) (int64, error) {
 
 // SRemCtx is the implementation of redis srem command.
 func (s *Redis) SRemCtx(ctx context.Context, key string, values ...interface{}) (val int64, err error) {
-	return s.client.SRem(ctx, key, values...).Result()
+	return s.client.SRem(ctx, s.key(key), values...).Result()
 }
 
 // SUnion is the implementation of redis sunion command.
@@ -143,7 +143,7 @@ func (s *Redis) SUnion(keys ...string) ([]string, error) {
 
 // SUnionCtx is the implementation of redis sunion command.
 func (s *Redis) SUnionCtx(ctx context.Context, keys ...string) (val []string, err error) {
-	return s.client.SUnion(ctx, keys...).Result()
+	return s.client.SUnion(ctx, s.keys(keys)...).Result()
 }
 
 // SUnionStore is the implementation of redis sunionstore command.
@@ -154,5 +154,5 @@ func (s *Redis) SUnionStore(destination string, keys ...string) (int64, error) {
 // SUnionStoreCtx is the implementation of redis sunionstore command.
 func (s *Redis) SUnionStoreCtx(ctx context.Context, destination string, keys ...string) (
 	val int64, err error) {
-	return s.client.SUnionStore(ctx, destination, keys...).Result()
+	return s.client.SUnionStore(ctx, s.key(destination), s.keys(keys)...).Result()
 }
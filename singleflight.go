@@ -0,0 +1,48 @@
+package rredis
+
+import "sync"
+
+type (
+	// singleFlight makes concurrent calls with the same key share one execution.
+	singleFlight struct {
+		lock  sync.Mutex
+		calls map[string]*flightCall
+	}
+
+	flightCall struct {
+		wg  sync.WaitGroup
+		val interface{}
+		err error
+	}
+)
+
+func newSingleFlight() *singleFlight {
+	return &singleFlight{
+		calls: make(map[string]*flightCall),
+	}
+}
+
+// Do executes fn once for concurrent callers with the same key, they all get its result.
+func (g *singleFlight) Do(key string, fn func() (interface{}, error)) (interface{}, error) {
+	g.lock.Lock()
+	if c, ok := g.calls[key]; ok {
+		g.lock.Unlock()
+		c.wg.Wait()
+		return c.val, c.err
+	}
+
+	c := new(flightCall)
+	c.wg.Add(1)
+	g.calls[key] = c
+	g.lock.Unlock()
+
+	defer func() {
+		g.lock.Lock()
+		delete(g.calls, key)
+		g.lock.Unlock()
+		c.wg.Done()
+	}()
+
+	c.val, c.err = fn()
+	return c.val, c.err
+}
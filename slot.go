@@ -0,0 +1,106 @@
+package rredis
+
+import (
+	"context"
+	"strings"
+
+	red "github.com/redis/go-redis/v9"
+)
+
+// slotCount is the number of hash slots of redis cluster.
+const slotCount = 16384
+
+// keySlot returns the cluster hash slot of key, only the hash tag between braces is hashed if any.
+func keySlot(key string) int {
+	return int(crc16(hashTag(key)) % slotCount)
+}
+
+// hashTag returns the part of key hashed by redis cluster.
+func hashTag(key string) string {
+	start := strings.IndexByte(key, '{')
+	if start < 0 {
+		return key
+	}
+
+	end := strings.IndexByte(key[start+1:], '}')
+	if end <= 0 {
+		return key
+	}
+
+	return key[start+1 : start+1+end]
+}
+
+// sameSlotKey returns key with suffix, hash tagged to stay in the cluster slot of key.
+// Keys with a closing brace but no hash tag can't be wrapped in one, use a hash tag with them in cluster mode.
+func sameSlotKey(key, suffix string) string {
+	if hashTag(key) != key || strings.IndexByte(key, '}') >= 0 {
+		return key + suffix
+	}
+
+	return "{" + key + "}" + suffix
+}
+
+// groupBySlot groups keys by their cluster hash slot, keeping their order within each slot.
+func groupBySlot(keys []string) map[int][]string {
+	groups := make(map[int][]string)
+	for _, key := range keys {
+		slot := keySlot(key)
+		groups[slot] = append(groups[slot], key)
+	}
+
+	return groups
+}
+
+// mget gets the values of the prefixed keys, in cluster mode with one MGET per slot in a pipeline.
+func (s *Redis) mget(ctx context.Context, keys []string) ([]interface{}, error) {
+	if _, ok := s.client.(*red.ClusterClient); !ok {
+		return s.client.MGet(ctx, keys...).Result()
+	}
+
+	groups := make(map[int][]int)
+	for i, key := range keys {
+		slot := keySlot(key)
+		groups[slot] = append(groups[slot], i)
+	}
+
+	cmds := make(map[int]*red.SliceCmd, len(groups))
+	_, err := s.client.Pipelined(ctx, func(pipe red.Pipeliner) error {
+		for slot, idxs := range groups {
+			slotKeys := make([]string, len(idxs))
+			for i, idx := range idxs {
+				slotKeys[i] = keys[idx]
+			}
+			cmds[slot] = pipe.MGet(ctx, slotKeys...)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	vals := make([]interface{}, len(keys))
+	for slot, idxs := range groups {
+		for i, val := range cmds[slot].Val() {
+			vals[idxs[i]] = val
+		}
+	}
+
+	return vals, nil
+}
+
+// crc16 is the CRC16-CCITT (XMODEM) checksum used by redis cluster.
+func crc16(s string) uint16 {
+	var crc uint16
+	for i := 0; i < len(s); i++ {
+		crc ^= uint16(s[i]) << 8
+		for j := 0; j < 8; j++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+
+	return crc
+}
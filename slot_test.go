@@ -0,0 +1,21 @@
+package rredis
+
+import "testing"
+
+func TestKeySlot(t *testing.T) {
+	tests := map[string]int{
+		"123456789":     12739,
+		"foo":           12182,
+		"{user1000}.a":  keySlot("user1000"),
+		"{user1000}.b":  keySlot("user1000"),
+		"foo{}{bar}":    keySlot("foo{}{bar}"),
+		"foo{{bar}}zap": keySlot("{bar"),
+		"foo{bar}{zap}": keySlot("bar"),
+	}
+
+	for key, want := range tests {
+		if got := keySlot(key); got != want {
+			t.Errorf("slot of %q: expected %d, got %d", key, want, got)
+		}
+	}
+}
@@ -25,7 +25,7 @@ func (s *Redis) ZAddFloat(key string, score float64, value string) (bool, error)
 // ZAddFloatCtx is the implementation of redis zadd command.
 func (s *Redis) ZAddFloatCtx(ctx context.Context, key string, score float64, value string) (
 	val bool, err error) {
-	v, err := s.client.ZAdd(ctx, key, red.Z{
+	v, err := s.client.ZAdd(ctx, s.key(key), red.Z{
 		Score:  score,
 		Member: value,
 	}).Result()
@@ -47,7 +47,7 @@ func (s *Redis) ZAddsCtx(ctx context.Context, key string, ps ...Pair) (val int64
 		zs = append(zs, z)
 	}
 
-	return s.client.ZAdd(ctx, key, zs...).Result()
+	return s.client.ZAdd(ctx, s.key(key), zs...).Result()
 }
 
 // ZCard is the implementation of redis zcard command.
@@ -57,7 +57,7 @@ func (s *Redis) ZCard(key string) (int64, error) {
 
 // ZCardCtx is the implementation of redis zcard command.
 func (s *Redis) ZCardCtx(ctx context.Context, key string) (val int64, err error) {
-	return s.client.ZCard(ctx, key).Result()
+	return s.client.ZCard(ctx, s.key(key)).Result()
 }
 
 // ZCount is the implementation of redis zcount command.
@@ -67,7 +67,7 @@ func (s *Redis) ZCount(key string, min, max string) (int64, error) {
 
 // ZCountCtx is the implementation of redis zcount command.
 func (s *Redis) ZCountCtx(ctx context.Context, key string, min, max string) (val int64, err error) {
-	return s.client.ZCount(ctx, key, min, max).Result()
+	return s.client.ZCount(ctx, s.key(key), min, max).Result()
 }
 
 // ZIncrBy is the implementation of redis zincrby command.
@@ -91,7 +91,7 @@ func (s *Redis) ZIncrByFloat(key string, increment float64, field string) (float
 // ZIncrByFloatCtx is the implementation of redis zincrby command.
 func (s *Redis) ZIncrByFloatCtx(ctx context.Context, key string, increment float64, field string) (
 	val float64, err error) {
-	return s.client.ZIncrBy(ctx, key, increment, field).Result()
+	return s.client.ZIncrBy(ctx, s.key(key), increment, field).Result()
 }
 
 // TODO Zinterstore
@@ -117,7 +117,7 @@ func (s *Redis) ZScoreFloat(key, value string) (float64, error) {
 
 // ZScoreFloatCtx is the implementation of redis zscore command score by float.
 func (s *Redis) ZScoreFloatCtx(ctx context.Context, key, value string) (val float64, err error) {
-	s.client.ZScore(ctx, key, value).Result()
+	s.client.ZScore(ctx, s.key(key), value).Result()
 	return
 }
 
@@ -130,7 +130,7 @@ func (s *Redis) ZScan(key string, cursor uint64, match string, count int64) (
 // ZScanCtx is the implementation of redis zscan command.
 func (s *Redis) ZScanCtx(ctx context.Context, key string, cursor uint64, match string, count int64) (
 	keys []string, cur uint64, err error) {
-	return s.client.ZScan(ctx, key, cursor, match, count).Result()
+	return s.client.ZScan(ctx, s.key(key), cursor, match, count).Result()
 }
 
 // ZRank is the implementation of redis zrank command.
@@ -140,7 +140,7 @@ func (s *Redis) ZRank(key, field string) (int64, error) {
 
 // ZRankCtx is the implementation of redis zrank command.
 func (s *Redis) ZRankCtx(ctx context.Context, key, field string) (val int64, err error) {
-	return s.client.ZRank(ctx, key, field).Result()
+	return s.client.ZRank(ctx, s.key(key), field).Result()
 }
 
 // ZRevRank is the implementation of redis zrevrank command.
@@ -150,7 +150,7 @@ func (s *Redis) ZRevRank(key, field string) (int64, error) {
 
 // ZRevRankCtx is the implementation of redis zrevrank command.
 func (s *Redis) ZRevRankCtx(ctx context.Context, key, field string) (val int64, err error) {
-	return s.client.ZRevRank(ctx, key, field).Result()
+	return s.client.ZRevRank(ctx, s.key(key), field).Result()
 }
 
 // ZRem is the implementation of redis zrem command.
@@ -160,7 +160,7 @@ func (s *Redis) ZRem(key string, values ...interface{}) (int64, error) {
 
 // ZRemCtx is the implementation of redis zrem command.
 func (s *Redis) ZRemCtx(ctx context.Context, key string, values ...interface{}) (val int64, err error) {
-	return s.client.ZRem(ctx, key, values...).Result()
+	return s.client.ZRem(ctx, s.key(key), values...).Result()
 }
 
 // ZRemRangeByScore is the implementation of redis zremrangebyscore command.
@@ -171,7 +171,7 @@ func (s *Redis) ZRemRangeByScore(key string, min, max string) (int64, error) {
 // ZRemRangeByScoreCtx is the implementation of redis zremrangebyscore command.
 func (s *Redis) ZRemRangeByScoreCtx(ctx context.Context, key string, min, max string) (
 	val int64, err error) {
-	return s.client.ZRemRangeByScore(ctx, key, min, max).Result()
+	return s.client.ZRemRangeByScore(ctx, s.key(key), min, max).Result()
 }
 
 // ZRemRangeByScoreInt64 is the implementation of redis zremrangebyscore command.
@@ -182,7 +182,7 @@ func (s *Redis) ZRemRangeByScoreInt64(key string, start, stop int64) (int64, err
 // ZRemRangeByScoreInt64Ctx is the implementation of redis zremrangebyscore command.
 func (s *Redis) ZRemRangeByScoreInt64Ctx(ctx context.Context, key string, start, stop int64) (
 	val int64, err error) {
-	return s.client.ZRemRangeByScore(ctx, key, strconv.FormatInt(start, 10),
+	return s.client.ZRemRangeByScore(ctx, s.key(key), strconv.FormatInt(start, 10),
 		strconv.FormatInt(stop, 10)).Result()
 }
 
@@ -196,7 +196,7 @@ func (s *Redis) ZRemRangeByRank(key string, start, stop int64) (int64, error) {
 // ZRemRangeByRankCtx is the implementation of redis zremrangebyrank command.
 func (s *Redis) ZRemRangeByRankCtx(ctx context.Context, key string, start, stop int64) (
 	val int64, err error) {
-	return s.client.ZRemRangeByRank(ctx, key, start, stop).Result()
+	return s.client.ZRemRangeByRank(ctx, s.key(key), start, stop).Result()
 }
 
 // TODO Zremrangebylex
@@ -211,7 +211,7 @@ func (s *Redis) ZRange(key string, start, stop int64) ([]string, error) {
 // ZRangeCtx is the implementation of redis zrange command.
 func (s *Redis) ZRangeCtx(ctx context.Context, key string, start, stop int64) (
 	val []string, err error) {
-	return s.client.ZRange(ctx, key, start, stop).Result()
+	return s.client.ZRange(ctx, s.key(key), start, stop).Result()
 }
 
 // ZRangeByScore is the implementation of redis zrangebyscore command.
@@ -222,7 +222,7 @@ func (s *Redis) ZRangeByScore(key string, min, max string) ([]string, error) {
 // ZRangeByScoreCtx is the implementation of redis zrangebyscore command.
 func (s *Redis) ZRangeByScoreCtx(ctx context.Context, key string, min, max string) (
 	val []string, err error) {
-	val, err = s.client.ZRangeByScore(ctx, key, &red.ZRangeBy{
+	val, err = s.client.ZRangeByScore(ctx, s.key(key), &red.ZRangeBy{
 		Min: min,
 		Max: max,
 	}).Result()
@@ -237,7 +237,7 @@ func (s *Redis) ZRangeByScoreAll(key string) ([]string, error) {
 // ZRangeByScoreAllCtx is the implementation of redis zrangebyscore command.
 func (s *Redis) ZRangeByScoreAllCtx(ctx context.Context, key string) (
 	val []string, err error) {
-	val, err = s.client.ZRangeByScore(ctx, key, &red.ZRangeBy{
+	val, err = s.client.ZRangeByScore(ctx, s.key(key), &red.ZRangeBy{
 		Min: "-inf",
 		Max: "+inf",
 	}).Result()
@@ -252,7 +252,7 @@ func (s *Redis) ZRangeByScoreAndLimit(key string, min, max string, page, size in
 // ZRangeByScoreAndLimitCtx is the implementation of redis zrangebyscore command.
 func (s *Redis) ZRangeByScoreAndLimitCtx(ctx context.Context, key string, min, max string, page, size int64) (
 	val []string, err error) {
-	val, err = s.client.ZRangeByScore(ctx, key, &red.ZRangeBy{
+	val, err = s.client.ZRangeByScore(ctx, s.key(key), &red.ZRangeBy{
 		Min:    min,
 		Max:    max,
 		Offset: page * size,
@@ -266,7 +266,7 @@ func (s *Redis) ZRangeByScoreInt64AndLimit(key string, min, max int64, page, siz
 }
 
 func (s *Redis) ZRangeByScoreInt64AndLimitCtx(ctx context.Context, key string, min, max int64, page, size int64) (val []string, err error) {
-	val, err = s.client.ZRangeByScore(ctx, key, &red.ZRangeBy{
+	val, err = s.client.ZRangeByScore(ctx, s.key(key), &red.ZRangeBy{
 		Min:    strconv.FormatInt(min, 10),
 		Max:    strconv.FormatInt(max, 10),
 		Offset: page * size,
@@ -283,7 +283,7 @@ func (s *Redis) ZRangeByScoreAllAndLimit(key string, page, size int64) ([]string
 // ZRangeByScoreAllAndLimitCtx is the implementation of redis zrangebyscore command.
 func (s *Redis) ZRangeByScoreAllAndLimitCtx(ctx context.Context, key string, page, size int64) (
 	val []string, err error) {
-	val, err = s.client.ZRangeByScore(ctx, key, &red.ZRangeBy{
+	val, err = s.client.ZRangeByScore(ctx, s.key(key), &red.ZRangeBy{
 		Min:    "-inf",
 		Max:    "+inf",
 		Offset: page * size,
@@ -300,7 +300,7 @@ func (s *Redis) ZRangeWithScores(key string, start, stop int64) ([]Pair, error)
 // ZRangeWithScoresCtx is the implementation of redis zrange command with scores.
 func (s *Redis) ZRangeWithScoresCtx(ctx context.Context, key string, start, stop int64) (
 	val []Pair, err error) {
-	v, err := s.client.ZRangeWithScores(ctx, key, start, stop).Result()
+	v, err := s.client.ZRangeWithScores(ctx, s.key(key), start, stop).Result()
 	val = toPairs(v)
 	return
 }
@@ -313,7 +313,7 @@ func (s *Redis) ZRangeWithScoresFloat(key string, start, stop int64) ([]FloatPai
 // ZRangeWithScoresFloatCtx is the implementation of redis zrange command with scores by float64.
 func (s *Redis) ZRangeWithScoresFloatCtx(ctx context.Context, key string, start, stop int64) (
 	val []FloatPair, err error) {
-	v, err := s.client.ZRangeWithScores(ctx, key, start, stop).Result()
+	v, err := s.client.ZRangeWithScores(ctx, s.key(key), start, stop).Result()
 	val = toFloatPairs(v)
 	return
 }
@@ -328,7 +328,7 @@ func (s *Redis) ZRangeByScoreWithScores(key string, min, max string) ([]Pair, er
 // ZRangeByScoreWithScoresCtx is the implementation of redis zrangebyscore command with scores.
 func (s *Redis) ZRangeByScoreWithScoresCtx(ctx context.Context, key string, min, max string) (
 	val []Pair, err error) {
-	v, err := s.client.ZRangeByScoreWithScores(ctx, key, &red.ZRangeBy{
+	v, err := s.client.ZRangeByScoreWithScores(ctx, s.key(key), &red.ZRangeBy{
 		Min: min,
 		Max: max,
 	}).Result()
@@ -344,7 +344,7 @@ func (s *Redis) ZRangeByScoreWithScoresAll(key string) ([]Pair, error) {
 // ZRangeByScoreWithScoresAllCtx is the implementation of redis zrangebyscore command with scores.
 func (s *Redis) ZRangeByScoreWithScoresAllCtx(ctx context.Context, key string) (
 	val []Pair, err error) {
-	v, err := s.client.ZRangeByScoreWithScores(ctx, key, &red.ZRangeBy{
+	v, err := s.client.ZRangeByScoreWithScores(ctx, s.key(key), &red.ZRangeBy{
 		Min: "-inf",
 		Max: "+inf",
 	}).Result()
@@ -360,7 +360,7 @@ func (s *Redis) ZRangeByScoreWithScoresInt64(key string, start, stop int64) ([]P
 // ZRangeByScoreWithScoresInt64Ctx is the implementation of redis zrangebyscore command with scores.
 func (s *Redis) ZRangeByScoreWithScoresInt64Ctx(ctx context.Context, key string, start, stop int64) (
 	val []Pair, err error) {
-	v, err := s.client.ZRangeByScoreWithScores(ctx, key, &red.ZRangeBy{
+	v, err := s.client.ZRangeByScoreWithScores(ctx, s.key(key), &red.ZRangeBy{
 		Min: strconv.FormatInt(start, 10),
 		Max: strconv.FormatInt(stop, 10),
 	}).Result()
@@ -376,7 +376,7 @@ func (s *Redis) ZRangeByScoreWithScoresFloat(key string, start, stop float64) ([
 // ZRangeByScoreWithScoresFloatCtx is the implementation of redis zrangebyscore command with scores by float.
 func (s *Redis) ZRangeByScoreWithScoresFloatCtx(ctx context.Context, key string, start, stop float64) (
 	val []FloatPair, err error) {
-	v, err := s.client.ZRangeByScoreWithScores(ctx, key, &red.ZRangeBy{
+	v, err := s.client.ZRangeByScoreWithScores(ctx, s.key(key), &red.ZRangeBy{
 		Min: strconv.FormatFloat(start, 'f', -1, 64),
 		Max: strconv.FormatFloat(stop, 'f', -1, 64),
 	}).Result()
@@ -393,7 +393,7 @@ func (s *Redis) ZRangeByScoreWithScoresAndLimit(key string, min, max string, pag
 // ZRangeByScoreWithScoresAndLimitCtx is the implementation of redis zrangebyscore command
 // with scores and limit.
 func (s *Redis) ZRangeByScoreWithScoresAndLimitCtx(ctx context.Context, key string, min, max string, page, size int64) (val []Pair, err error) {
-	v, err := s.client.ZRangeByScoreWithScores(ctx, key, &red.ZRangeBy{
+	v, err := s.client.ZRangeByScoreWithScores(ctx, s.key(key), &red.ZRangeBy{
 		Min:    min,
 		Max:    max,
 		Offset: page * size,
@@ -412,7 +412,7 @@ func (s *Redis) ZRangeByScoreWithScoresAllAndLimit(key string, page, size int64)
 // ZRangeByScoreWithScoresAllAndLimitCtx is the implementation of redis zrangebyscore command
 // with scores and limit.
 func (s *Redis) ZRangeByScoreWithScoresAllAndLimitCtx(ctx context.Context, key string, page, size int64) (val []Pair, err error) {
-	v, err := s.client.ZRangeByScoreWithScores(ctx, key, &red.ZRangeBy{
+	v, err := s.client.ZRangeByScoreWithScores(ctx, s.key(key), &red.ZRangeBy{
 		Min:    "-inf",
 		Max:    "+inf",
 		Offset: page * size,
@@ -433,7 +433,7 @@ func (s *Redis) ZRangeByScoreWithScoresInt64AndLimit(key string, start, stop int
 // with scores and limit.
 func (s *Redis) ZRangeByScoreWithScoresInt64AndLimitCtx(ctx context.Context, key string, start,
 	stop int64, page, size int64) (val []Pair, err error) {
-	v, err := s.client.ZRangeByScoreWithScores(ctx, key, &red.ZRangeBy{
+	v, err := s.client.ZRangeByScoreWithScores(ctx, s.key(key), &red.ZRangeBy{
 		Min:    strconv.FormatInt(start, 10),
 		Max:    strconv.FormatInt(stop, 10),
 		Offset: page * size,
@@ -454,7 +454,7 @@ func (s *Redis) ZRangeByScoreWithScoresFloatAndLimit(key string, start, stop flo
 // with scores by float and limit.
 func (s *Redis) ZRangeByScoreWithScoresFloatAndLimitCtx(ctx context.Context, key string, start,
 	stop float64, page, size int64) (val []FloatPair, err error) {
-	v, err := s.client.ZRangeByScoreWithScores(ctx, key, &red.ZRangeBy{
+	v, err := s.client.ZRangeByScoreWithScores(ctx, s.key(key), &red.ZRangeBy{
 		Min:    strconv.FormatFloat(start, 'f', -1, 64),
 		Max:    strconv.FormatFloat(stop, 'f', -1, 64),
 		Offset: page * size,
@@ -476,7 +476,7 @@ func (s *Redis) ZRangeByScoreWithScoresInt64AllLimit(key string, start, stop int
 func (s *Redis) ZRangeByScoreWithScoresInt64AllLimitCtx(ctx context.Context, key string, start, stop int64,
 	page, size int64) (val []Pair, err error) {
 
-	v, err := s.client.ZRangeByScoreWithScores(ctx, key, &red.ZRangeBy{
+	v, err := s.client.ZRangeByScoreWithScores(ctx, s.key(key), &red.ZRangeBy{
 		Min:    strconv.FormatInt(start, 10),
 		Max:    strconv.FormatInt(stop, 10),
 		Offset: page * size,
@@ -495,7 +495,7 @@ func (s *Redis) ZRangeByScoreWithScoresFloatAllLimit(key string, start, stop flo
 // ZRangeByScoreWithScoresFloatAllLimitCtx is the implementation of redis ZRangeByScoreWithScores command
 // with scores by float and limit.
 func (s *Redis) ZRangeByScoreWithScoresFloatAllLimitCtx(ctx context.Context, key string, start, stop float64, page, size int64) (val []FloatPair, err error) {
-	v, err := s.client.ZRangeByScoreWithScores(ctx, key, &red.ZRangeBy{
+	v, err := s.client.ZRangeByScoreWithScores(ctx, s.key(key), &red.ZRangeBy{
 		Min:    strconv.FormatFloat(start, 'f', -1, 64),
 		Max:    strconv.FormatFloat(stop, 'f', -1, 64),
 		Offset: page * size,
@@ -515,7 +515,7 @@ func (s *Redis) ZRevRange(key string, start, stop int64) ([]string, error) {
 // ZRevRangeCtx is the implementation of redis zrevrange command.
 func (s *Redis) ZRevRangeCtx(ctx context.Context, key string, start, stop int64) (
 	val []string, err error) {
-	return s.client.ZRevRange(ctx, key, start, stop).Result()
+	return s.client.ZRevRange(ctx, s.key(key), start, stop).Result()
 }
 
 // ZRevRangeByScore is the implementation of redis zrevrangebyscore command.
@@ -526,7 +526,7 @@ func (s *Redis) ZRevRangeByScore(key string, min, max string) ([]string, error)
 // ZRevRangeByScoreCtx is the implementation of redis zrevrangebyscore command.
 func (s *Redis) ZRevRangeByScoreCtx(ctx context.Context, key string, min, max string) (
 	val []string, err error) {
-	val, err = s.client.ZRevRangeByScore(ctx, key, &red.ZRangeBy{
+	val, err = s.client.ZRevRangeByScore(ctx, s.key(key), &red.ZRangeBy{
 		Min: min,
 		Max: max,
 	}).Result()
@@ -541,7 +541,7 @@ func (s *Redis) ZRevRangeByScoreAll(key string) ([]string, error) {
 // ZRevRangeByScoreAllCtx is the implementation of redis zrevrangebyscore command.
 func (s *Redis) ZRevRangeByScoreAllCtx(ctx context.Context, key string) (
 	val []string, err error) {
-	val, err = s.client.ZRevRangeByScore(ctx, key, &red.ZRangeBy{
+	val, err = s.client.ZRevRangeByScore(ctx, s.key(key), &red.ZRangeBy{
 		Min: "-inf",
 		Max: "+inf",
 	}).Result()
@@ -556,7 +556,7 @@ func (s *Redis) ZRevRangeByScoreAndLimit(key string, min, max string, page, size
 // ZRevRangeByScoreAndLimitCtx is the implementation of redis zrevrangebyscore command.
 func (s *Redis) ZRevRangeByScoreAndLimitCtx(ctx context.Context, key string, min, max string, page, size int64) (
 	val []string, err error) {
-	val, err = s.client.ZRevRangeByScore(ctx, key, &red.ZRangeBy{
+	val, err = s.client.ZRevRangeByScore(ctx, s.key(key), &red.ZRangeBy{
 		Min:    min,
 		Max:    max,
 		Offset: page * size,
@@ -573,7 +573,7 @@ func (s *Redis) ZRevRangeByScoreAllAndLimit(key string, page, size int64) ([]str
 // ZRevRangeByScoreAllAndLimitCtx is the implementation of redis zrevrangebyscore command.
 func (s *Redis) ZRevRangeByScoreAllAndLimitCtx(ctx context.Context, key string, page, size int64) (
 	val []string, err error) {
-	val, err = s.client.ZRangeByScore(ctx, key, &red.ZRangeBy{
+	val, err = s.client.ZRangeByScore(ctx, s.key(key), &red.ZRangeBy{
 		Min:    "-inf",
 		Max:    "+inf",
 		Offset: page * size,
@@ -590,7 +590,7 @@ func (s *Redis) ZRevRangeByScoreWithScores(key string, min, max string) ([]Pair,
 // ZRevRangeByScoreWithScoresCtx is the implementation of redis zrevrangebyscore command with scores.
 func (s *Redis) ZRevRangeByScoreWithScoresCtx(ctx context.Context, key string, min, max string) (
 	val []Pair, err error) {
-	v, err := s.client.ZRevRangeByScoreWithScores(ctx, key, &red.ZRangeBy{
+	v, err := s.client.ZRevRangeByScoreWithScores(ctx, s.key(key), &red.ZRangeBy{
 		Min: min,
 		Max: max,
 	}).Result()
@@ -606,7 +606,7 @@ func (s *Redis) ZRevRangeByScoreWithScoresAll(key string) ([]Pair, error) {
 // ZRevRangeByScoreWithScoresAllCtx is the implementation of redis zrevrangebyscore command with scores.
 func (s *Redis) ZRevRangeByScoreWithScoresAllCtx(ctx context.Context, key string) (
 	val []Pair, err error) {
-	v, err := s.client.ZRevRangeByScoreWithScores(ctx, key, &red.ZRangeBy{
+	v, err := s.client.ZRevRangeByScoreWithScores(ctx, s.key(key), &red.ZRangeBy{
 		Min: "-inf",
 		Max: "+inf",
 	}).Result()
@@ -622,7 +622,7 @@ func (s *Redis) ZRevRangeByScoreWithScoresInt64(key string, start, stop int64) (
 // ZRevRangeByScoreWithScoresInt64Ctx is the implementation of redis zrevrangebyscore command with scores.
 func (s *Redis) ZRevRangeByScoreWithScoresInt64Ctx(ctx context.Context, key string, start, stop int64) (
 	val []Pair, err error) {
-	v, err := s.client.ZRevRangeByScoreWithScores(ctx, key, &red.ZRangeBy{
+	v, err := s.client.ZRevRangeByScoreWithScores(ctx, s.key(key), &red.ZRangeBy{
 		Min: strconv.FormatInt(start, 10),
 		Max: strconv.FormatInt(stop, 10),
 	}).Result()
@@ -639,7 +639,7 @@ func (s *Redis) ZRevRangeByScoreWithScoresFloat(key string, start, stop float64)
 // ZRevRangeByScoreWithScoresFloatCtx is the implementation of redis zrevrangebyscore command with scores by float.
 func (s *Redis) ZRevRangeByScoreWithScoresFloatCtx(ctx context.Context, key string,
 	start, stop float64) (val []FloatPair, err error) {
-	v, err := s.client.ZRevRangeByScoreWithScores(ctx, key, &red.ZRangeBy{
+	v, err := s.client.ZRevRangeByScoreWithScores(ctx, s.key(key), &red.ZRangeBy{
 		Min: strconv.FormatFloat(start, 'f', -1, 64),
 		Max: strconv.FormatFloat(stop, 'f', -1, 64),
 	}).Result()
@@ -659,7 +659,7 @@ func (s *Redis) ZRevRangeByScoreWithScoresAndLimit(key string, min, max string,
 func (s *Redis) ZRevRangeByScoreWithScoresAndLimitCtx(ctx context.Context, key string,
 	min, max string, page, size int64) (val []Pair, err error) {
 
-	v, err := s.client.ZRevRangeByScoreWithScores(ctx, key, &red.ZRangeBy{
+	v, err := s.client.ZRevRangeByScoreWithScores(ctx, s.key(key), &red.ZRangeBy{
 		Min:    min,
 		Max:    max,
 		Offset: page * size,
@@ -681,7 +681,7 @@ func (s *Redis) ZRevRangeByScoreWithScoresAllAndLimit(key string,
 func (s *Redis) ZRevRangeByScoreWithScoresAllAndLimitCtx(ctx context.Context, key string,
 	page, size int64) (val []Pair, err error) {
 
-	v, err := s.client.ZRevRangeByScoreWithScores(ctx, key, &red.ZRangeBy{
+	v, err := s.client.ZRevRangeByScoreWithScores(ctx, s.key(key), &red.ZRangeBy{
 		Min:    "-inf",
 		Max:    "+inf",
 		Offset: page * size,
@@ -703,7 +703,7 @@ func (s *Redis) ZRevRangeByScoreWithScoresInt64AndLimit(key string, start, stop
 func (s *Redis) ZRevRangeByScoreWithScoresInt64AndLimitCtx(ctx context.Context, key string,
 	start, stop int64, page, size int64) (val []Pair, err error) {
 
-	v, err := s.client.ZRevRangeByScoreWithScores(ctx, key, &red.ZRangeBy{
+	v, err := s.client.ZRevRangeByScoreWithScores(ctx, s.key(key), &red.ZRangeBy{
 		Min:    strconv.FormatInt(start, 10),
 		Max:    strconv.FormatInt(stop, 10),
 		Offset: page * size,
@@ -726,7 +726,7 @@ func (s *Redis) ZRevRangeByScoreWithScoresFloatAndLimit(key string, start, stop
 func (s *Redis) ZRevRangeByScoreWithScoresFloatAndLimitCtx(ctx context.Context, key string,
 	start, stop float64, page, size int64) (val []FloatPair, err error) {
 
-	v, err := s.client.ZRevRangeByScoreWithScores(ctx, key, &red.ZRangeBy{
+	v, err := s.client.ZRevRangeByScoreWithScores(ctx, s.key(key), &red.ZRangeBy{
 		Min:    strconv.FormatFloat(start, 'f', -1, 64),
 		Max:    strconv.FormatFloat(stop, 'f', -1, 64),
 		Offset: page * size,
@@ -746,7 +746,7 @@ func (s *Redis) ZRevRangeByScoreWithScoresInt64AllLimit(key string, page, size i
 // with scores and limit.
 func (s *Redis) ZRevRangeByScoreWithScoresInt64AllLimitCtx(ctx context.Context, key string,
 	page, size int64) (val []Pair, err error) {
-	v, err := s.client.ZRevRangeByScoreWithScores(ctx, key, &red.ZRangeBy{
+	v, err := s.client.ZRevRangeByScoreWithScores(ctx, s.key(key), &red.ZRangeBy{
 		Min:    "-inf",
 		Max:    "+inf",
 		Offset: page * size,
@@ -769,7 +769,7 @@ func (s *Redis) ZRevRangeByScoreWithScoresFloatAllLimit(key string, start, stop
 func (s *Redis) ZRevRangeByScoreWithScoresFloatAllLimitCtx(ctx context.Context, key string,
 	page, size int64) (val []FloatPair, err error) {
 
-	v, err := s.client.ZRevRangeByScoreWithScores(ctx, key, &red.ZRangeBy{
+	v, err := s.client.ZRevRangeByScoreWithScores(ctx, s.key(key), &red.ZRangeBy{
 		Min:    "-inf",
 		Max:    "+inf",
 		Offset: page * size,
@@ -789,7 +789,13 @@ func (s *Redis) ZUnionStore(dest string, store *ZStore) (int64, error) {
 // ZUnionStoreCtx is the implementation of redis zunionstore command.
 func (s *Redis) ZUnionStoreCtx(ctx context.Context, dest string, store *ZStore) (
 	val int64, err error) {
-	return s.client.ZUnionStore(ctx, dest, store).Result()
+	if len(s.prefix) > 0 {
+		prefixed := *store
+		prefixed.Keys = s.keys(store.Keys)
+		store = &prefixed
+	}
+
+	return s.client.ZUnionStore(ctx, s.key(dest), store).Result()
 }
 
 // ---------
@@ -799,7 +805,7 @@ func (s *Redis) ZPopMin(key string, count int64) (val []Pair, err error) {
 }
 
 func (s *Redis) ZPopMinCtx(ctx context.Context, key string, count int64) (val []Pair, err error) {
-	v, err := s.client.ZPopMin(ctx, key, count).Result()
+	v, err := s.client.ZPopMin(ctx, s.key(key), count).Result()
 	val = toPairs(v)
 	return
 }
@@ -809,7 +815,7 @@ func (s *Redis) ZPopMax(key string, count int64) (val []Pair, err error) {
 }
 
 func (s *Redis) ZPopMaxCtx(ctx context.Context, key string, count int64) (val []Pair, err error) {
-	v, err := s.client.ZPopMax(ctx, key, count).Result()
+	v, err := s.client.ZPopMax(ctx, s.key(key), count).Result()
 	val = toPairs(v)
 	return
 }
@@ -828,7 +834,7 @@ func (s *Redis) ZMScore(key string, members ...string) (val []float64, err error
 }
 
 func (s *Redis) ZMScoreCtx(ctx context.Context, key string, members ...string) (val []float64, err error) {
-	val, err = s.client.ZMScore(ctx, key, members...).Result()
+	val, err = s.client.ZMScore(ctx, s.key(key), members...).Result()
 	return
 }
 
@@ -837,12 +843,12 @@ func (s *Redis) ZRandMember(key string, count int) (val []string, err error) {
 }
 
 func (s *Redis) ZRandMemberCtx(ctx context.Context, key string, count int) (val []string, err error) {
-	val, err = s.client.ZRandMember(ctx, key, count).Result()
+	val, err = s.client.ZRandMember(ctx, s.key(key), count).Result()
 	return
 }
 
 func (s *Redis) ZRandMemberWithScoresCtx(ctx context.Context, key string, count int) (val []Pair, err error) {
-	v, err := s.client.ZRandMemberWithScores(ctx, key, count).Result()
+	v, err := s.client.ZRandMemberWithScores(ctx, s.key(key), count).Result()
 	val = toPairs(v)
 	return
 }
@@ -0,0 +1,137 @@
+package rredis
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+type (
+	// CacheStats are the counters of the cache helpers in a namespace.
+	CacheStats struct {
+		Hits        int64
+		Misses      int64
+		Loads       int64
+		LoadErrors  int64
+		StaleServes int64
+		// LoadLatency is the total time spent in loaders, failed ones included.
+		LoadLatency time.Duration
+	}
+
+	// cacheStats counts the cache events by namespace, and forwards them to recorder if any.
+	cacheStats struct {
+		lock       sync.Mutex
+		namespaces map[string]*namespaceStats
+		recorder   CacheMetricsRecorder
+	}
+
+	namespaceStats struct {
+		hits        atomic.Int64
+		misses      atomic.Int64
+		loads       atomic.Int64
+		loadErrors  atomic.Int64
+		staleServes atomic.Int64
+		loadLatency atomic.Int64
+	}
+)
+
+// AvgLoadLatency returns the average latency of loaders.
+func (s CacheStats) AvgLoadLatency() time.Duration {
+	if loads := s.Loads + s.LoadErrors; loads > 0 {
+		return s.LoadLatency / time.Duration(loads)
+	}
+
+	return 0
+}
+
+// HitRatio returns the ratio of lookups that found a cached value.
+func (s CacheStats) HitRatio() float64 {
+	if lookups := s.Hits + s.Misses; lookups > 0 {
+		return float64(s.Hits) / float64(lookups)
+	}
+
+	return 0
+}
+
+// Stats returns a snapshot of the stats of GetWithCache, GetManyWithCache and Cache,
+// by namespace, that is the key prefix of the Redis they use, see WithNamespace.
+// Namespaced views of a Redis share their stats.
+func (s *Redis) Stats() map[string]CacheStats {
+	return s.stats.snapshot()
+}
+
+func newCacheStats() *cacheStats {
+	return &cacheStats{
+		namespaces: make(map[string]*namespaceStats),
+	}
+}
+
+func (c *cacheStats) hit(namespace string, count int) {
+	if count > 0 {
+		c.get(namespace).hits.Add(int64(count))
+		c.record(namespace, CacheHit, count, 0)
+	}
+}
+
+func (c *cacheStats) miss(namespace string, count int) {
+	if count > 0 {
+		c.get(namespace).misses.Add(int64(count))
+		c.record(namespace, CacheMiss, count, 0)
+	}
+}
+
+func (c *cacheStats) stale(namespace string) {
+	c.get(namespace).staleServes.Add(1)
+	c.record(namespace, CacheStaleServe, 1, 0)
+}
+
+// load counts a loader call that took duration, ErrNotFound isn't a load error.
+func (c *cacheStats) load(namespace string, duration time.Duration, err error) {
+	ns := c.get(namespace)
+	ns.loadLatency.Add(int64(duration))
+	if err != nil && err != ErrNotFound {
+		ns.loadErrors.Add(1)
+		c.record(namespace, CacheLoadError, 1, duration)
+	} else {
+		ns.loads.Add(1)
+		c.record(namespace, CacheLoad, 1, duration)
+	}
+}
+
+func (c *cacheStats) record(namespace, event string, count int, duration time.Duration) {
+	if c.recorder != nil {
+		c.recorder.ObserveCache(namespace, event, count, duration)
+	}
+}
+
+func (c *cacheStats) get(namespace string) *namespaceStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	ns, ok := c.namespaces[namespace]
+	if !ok {
+		ns = new(namespaceStats)
+		c.namespaces[namespace] = ns
+	}
+
+	return ns
+}
+
+func (c *cacheStats) snapshot() map[string]CacheStats {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	ret := make(map[string]CacheStats, len(c.namespaces))
+	for name, ns := range c.namespaces {
+		ret[name] = CacheStats{
+			Hits:        ns.hits.Load(),
+			Misses:      ns.misses.Load(),
+			Loads:       ns.loads.Load(),
+			LoadErrors:  ns.loadErrors.Load(),
+			StaleServes: ns.staleServes.Load(),
+			LoadLatency: time.Duration(ns.loadLatency.Load()),
+		}
+	}
+
+	return ret
+}
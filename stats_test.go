@@ -0,0 +1,53 @@
+package rredis
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCacheStats(t *testing.T) {
+	recorder := NewPrometheusRecorder("test")
+	stats := newCacheStats()
+	stats.recorder = recorder
+
+	stats.hit("users:", 3)
+	stats.miss("users:", 2)
+	stats.miss("users:", 0)
+	stats.load("users:", 10*time.Millisecond, nil)
+	stats.load("users:", 30*time.Millisecond, ErrNotFound)
+	stats.load("users:", 20*time.Millisecond, errors.New("boom"))
+	stats.stale("orders:")
+
+	snapshot := stats.snapshot()
+	users := snapshot["users:"]
+	want := CacheStats{Hits: 3, Misses: 2, Loads: 2, LoadErrors: 1, LoadLatency: 60 * time.Millisecond}
+	if users != want {
+		t.Fatalf("expected %+v, got %+v", want, users)
+	}
+	if users.AvgLoadLatency() != 20*time.Millisecond {
+		t.Fatalf("unexpected average load latency %s", users.AvgLoadLatency())
+	}
+	if users.HitRatio() != 0.6 {
+		t.Fatalf("unexpected hit ratio %f", users.HitRatio())
+	}
+	if snapshot["orders:"].StaleServes != 1 {
+		t.Fatalf("expected a stale serve, got %+v", snapshot["orders:"])
+	}
+
+	var b strings.Builder
+	if _, err := recorder.WriteTo(&b); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{
+		`test_redis_cache_events_total{namespace="users:",event="hit"} 3`,
+		`test_redis_cache_events_total{namespace="users:",event="load_error"} 1`,
+		`test_redis_cache_events_total{namespace="orders:",event="stale"} 1`,
+		`test_redis_cache_load_duration_seconds_count{namespace="users:"} 3`,
+	} {
+		if !strings.Contains(b.String(), line) {
+			t.Errorf("expected %q in:\n%s", line, b.String())
+		}
+	}
+}
@@ -11,40 +11,63 @@ package rredis
 import (
 	"context"
 	"github.com/redis/go-redis/v9"
+	"strings"
 )
 
 func (s *Redis) XAdd(ctx context.Context, a *redis.XAddArgs) (string, error) {
+	if len(s.prefix) > 0 {
+		prefixed := *a
+		prefixed.Stream = s.key(a.Stream)
+		a = &prefixed
+	}
+
 	return s.client.XAdd(ctx, a).Result()
 }
 
 func (s *Redis) XDel(ctx context.Context, stream string, ids ...string) error {
-	return s.client.XDel(ctx, stream, ids...).Err()
+	return s.client.XDel(ctx, s.key(stream), ids...).Err()
 }
 
 func (s *Redis) XLen(ctx context.Context, stream string) (int64, error) {
-	return s.client.XLen(ctx, stream).Result()
+	return s.client.XLen(ctx, s.key(stream)).Result()
 }
 
 func (s *Redis) XGroupCreateMkStream(ctx context.Context, stream, group, start string) error {
-	return s.client.XGroupCreateMkStream(ctx, stream, group, start).Err()
+	return s.client.XGroupCreateMkStream(ctx, s.key(stream), group, start).Err()
 }
 
 func (s *Redis) XReadGroup(ctx context.Context, a *redis.XReadGroupArgs) ([]redis.XStream, error) {
-	return s.client.XReadGroup(ctx, a).Result()
+	if len(s.prefix) == 0 {
+		return s.client.XReadGroup(ctx, a).Result()
+	}
+
+	// streams are followed by their ids
+	prefixed := *a
+	prefixed.Streams = append([]string(nil), a.Streams...)
+	for i := 0; i < len(prefixed.Streams)/2; i++ {
+		prefixed.Streams[i] = s.key(prefixed.Streams[i])
+	}
+
+	val, err := s.client.XReadGroup(ctx, &prefixed).Result()
+	for i := range val {
+		val[i].Stream = strings.TrimPrefix(val[i].Stream, s.prefix)
+	}
+
+	return val, err
 }
 
 func (s *Redis) XAck(ctx context.Context, stream, group string, ids ...string) error {
-	return s.client.XAck(ctx, stream, group, ids...).Err()
+	return s.client.XAck(ctx, s.key(stream), group, ids...).Err()
 }
 
 func (s *Redis) XPending(ctx context.Context, stream, group string) (*redis.XPending, error) {
-	return s.client.XPending(ctx, stream, group).Result()
+	return s.client.XPending(ctx, s.key(stream), group).Result()
 }
 
 func (s *Redis) XTrimMaxLenApprox(ctx context.Context, key string, maxLen, limit int64) error {
-	return s.client.XTrimMaxLenApprox(ctx, key, maxLen, limit).Err()
+	return s.client.XTrimMaxLenApprox(ctx, s.key(key), maxLen, limit).Err()
 }
 
 func (s *Redis) XInfoStream(ctx context.Context, key string) (*redis.XInfoStream, error) {
-	return s.client.XInfoStream(ctx, key).Result()
+	return s.client.XInfoStream(ctx, s.key(key)).Result()
 }
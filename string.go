@@ -13,7 +13,7 @@ func (s *Redis) Set(key string, value interface{}) error {
 
 // SetCtx is the implementation of redis set command.
 func (s *Redis) SetCtx(ctx context.Context, key string, value interface{}) error {
-	return s.client.Set(ctx, key, value, 0).Err()
+	return s.client.Set(ctx, s.key(key), value, 0).Err()
 }
 
 // Get is the implementation of redis get command.
@@ -23,7 +23,11 @@ func (s *Redis) Get(key string) (string, error) {
 
 // GetCtx is the implementation of redis get command.
 func (s *Redis) GetCtx(ctx context.Context, key string) (val string, err error) {
-	if val, err = s.client.Get(ctx, key).Result(); err == red.Nil {
+	if s.tracker != nil {
+		return s.tracker.get(ctx, s.key(key))
+	}
+
+	if val, err = s.client.Get(ctx, s.key(key)).Result(); err == red.Nil {
 		return val, nil
 	} else if err != nil {
 		return "", err
@@ -42,10 +46,12 @@ func (s *Redis) GetSet(key string, value interface{}) (string, error) {
 
 // GetSetCtx is the implementation of redis getset command.
 func (s *Redis) GetSetCtx(ctx context.Context, key string, value interface{}) (val string, err error) {
-	if val, err = s.client.GetSet(ctx, key, value).Result(); err == red.Nil {
+	if val, err = s.client.GetSet(ctx, s.key(key), value).Result(); err == red.Nil {
 		return val, nil
+	} else if err != nil {
+		return "", err
 	}
-	return "", err
+	return val, nil
 }
 
 // GetBit is the implementation of redis getbit command.
@@ -55,7 +61,7 @@ func (s *Redis) GetBit(key string, offset int64) (int64, error) {
 
 // GetBitCtx is the implementation of redis getbit command.
 func (s *Redis) GetBitCtx(ctx context.Context, key string, offset int64) (val int64, err error) {
-	return s.client.GetBit(ctx, key, offset).Result()
+	return s.client.GetBit(ctx, s.key(key), offset).Result()
 }
 
 // MGet is the implementation of redis mget command.
@@ -65,29 +71,33 @@ func (s *Redis) MGet(keys ...string) ([]string, error) {
 
 // MGetCtx is the implementation of redis mget command.
 func (s *Redis) MGetCtx(ctx context.Context, keys ...string) (val []string, err error) {
-	v, err := s.client.MGet(ctx, keys...).Result()
+	if s.tracker != nil {
+		return s.tracker.mget(ctx, s.keys(keys))
+	}
+
+	v, err := s.client.MGet(ctx, s.keys(keys)...).Result()
 	val = toStrings(v)
 	return
 }
 
 // MGet is the implementation of redis mset command.
 func (s *Redis) MSet(values ...interface{}) (string, error) {
-	return s.MSetCtx(s.ctx, values)
+	return s.MSetCtx(s.ctx, values...)
 }
 
 // MGetCtx is the implementation of redis mset command.
 func (s *Redis) MSetCtx(ctx context.Context, values ...interface{}) (val string, err error) {
-	return s.client.MSet(ctx, values).Result()
+	return s.client.MSet(ctx, s.pairs(values)...).Result()
 }
 
 // MSetNx is the implementation of redis msetnx command.
 func (s *Redis) MSetNx(values ...interface{}) (bool, error) {
-	return s.MSetNxCtx(s.ctx, values)
+	return s.MSetNxCtx(s.ctx, values...)
 }
 
 // MSetNxCtx is the implementation of redis msetnx command.
 func (s *Redis) MSetNxCtx(ctx context.Context, values ...interface{}) (val bool, err error) {
-	return s.client.MSetNX(ctx, values).Result()
+	return s.client.MSetNX(ctx, s.pairs(values)...).Result()
 }
 
 // SetBit is the implementation of redis setbit command.
@@ -97,7 +107,7 @@ func (s *Redis) SetBit(key string, offset int64, value int) (int64, error) {
 
 // SetBitCtx is the implementation of redis setbit command.
 func (s *Redis) SetBitCtx(ctx context.Context, key string, offset int64, value int) (val int64, err error) {
-	return s.client.SetBit(ctx, key, offset, value).Result()
+	return s.client.SetBit(ctx, s.key(key), offset, value).Result()
 }
 
 // SetEx is the implementation of redis setex command.
@@ -107,7 +117,7 @@ func (s *Redis) SetEx(key string, value interface{}, seconds int64) error {
 
 // SetExCtx is the implementation of redis setex command.
 func (s *Redis) SetExCtx(ctx context.Context, key string, value interface{}, seconds int64) error {
-	return s.client.Set(ctx, key, value, time.Duration(seconds)*time.Second).Err()
+	return s.client.Set(ctx, s.key(key), value, time.Duration(seconds)*time.Second).Err()
 }
 
 // SetNx is the implementation of redis setnx command.
@@ -117,7 +127,7 @@ func (s *Redis) SetNx(key string, value interface{}) (bool, error) {
 
 // SetNxCtx is the implementation of redis setnx command.
 func (s *Redis) SetNxCtx(ctx context.Context, key string, value interface{}) (val bool, err error) {
-	return s.client.SetNX(ctx, key, value, 0).Result()
+	return s.client.SetNX(ctx, s.key(key), value, 0).Result()
 }
 
 // SetNxEx is the implementation of redis setnx command with expire.
@@ -127,7 +137,7 @@ func (s *Redis) SetNxEx(key string, value interface{}, seconds int64) (bool, err
 
 // SetNxExCtx is the implementation of redis setnx command with expire.
 func (s *Redis) SetNxExCtx(ctx context.Context, key string, value interface{}, seconds int64) (val bool, err error) {
-	return s.client.SetNX(ctx, key, value, time.Duration(seconds)*time.Second).Result()
+	return s.client.SetNX(ctx, s.key(key), value, time.Duration(seconds)*time.Second).Result()
 }
 
 // TODO strlen
@@ -139,7 +149,7 @@ func (s *Redis) Incr(key string) (int64, error) {
 
 // IncrCtx is the implementation of redis incr command.
 func (s *Redis) IncrCtx(ctx context.Context, key string) (val int64, err error) {
-	return s.client.Incr(ctx, key).Result()
+	return s.client.Incr(ctx, s.key(key)).Result()
 }
 
 // IncrBy is the implementation of redis incrby command.
@@ -149,7 +159,7 @@ func (s *Redis) IncrBy(key string, increment int64) (int64, error) {
 
 // IncrByCtx is the implementation of redis incrby command.
 func (s *Redis) IncrByCtx(ctx context.Context, key string, increment int64) (val int64, err error) {
-	return s.client.IncrBy(ctx, key, increment).Result()
+	return s.client.IncrBy(ctx, s.key(key), increment).Result()
 }
 
 // IncrByFloat is the implementation of redis incrbyfloat command.
@@ -159,7 +169,7 @@ func (s *Redis) IncrByFloat(key string, increment float64) (float64, error) {
 
 // IncrByFloatCtx is the implementation of redis incrbyfloat command.
 func (s *Redis) IncrByFloatCtx(ctx context.Context, key string, increment float64) (val float64, err error) {
-	return s.client.IncrByFloat(ctx, key, increment).Result()
+	return s.client.IncrByFloat(ctx, s.key(key), increment).Result()
 }
 
 // Decr is the implementation of redis decr command.
@@ -169,7 +179,7 @@ func (s *Redis) Decr(key string) (int64, error) {
 
 // DecrCtx is the implementation of redis decr command.
 func (s *Redis) DecrCtx(ctx context.Context, key string) (val int64, err error) {
-	return s.client.Decr(ctx, key).Result()
+	return s.client.Decr(ctx, s.key(key)).Result()
 }
 
 // DecrBy is the implementation of redis decrby command.
@@ -179,7 +189,7 @@ func (s *Redis) DecrBy(key string, decrement int64) (int64, error) {
 
 // DecrByCtx is the implementation of redis decrby command.
 func (s *Redis) DecrByCtx(ctx context.Context, key string, decrement int64) (val int64, err error) {
-	return s.client.DecrBy(ctx, key, decrement).Result()
+	return s.client.DecrBy(ctx, s.key(key), decrement).Result()
 }
 
 // Append is the implementation of redis append command.
@@ -189,5 +199,5 @@ func (s *Redis) Append(key string, value string) (val int64, err error) {
 
 // AppendCtx is the implementation of redis append command.
 func (s *Redis) AppendCtx(ctx context.Context, key string, value string) (val int64, err error) {
-	return s.client.Append(ctx, key, value).Result()
+	return s.client.Append(ctx, s.key(key), value).Result()
 }
@@ -0,0 +1,419 @@
+package rredis
+
+import (
+	"context"
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	red "github.com/redis/go-redis/v9"
+)
+
+const structTag = "redis"
+
+var (
+	// ErrNotStruct is an error that indicates a value that's not a struct or a pointer to a struct.
+	ErrNotStruct = errors.New("value is not a struct")
+
+	timeType            = reflect.TypeOf(time.Time{})
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+	// structFields caches the fields of struct types.
+	structFields sync.Map
+	fieldCodecs  = map[string]Codec{
+		"json":    JSONCodec{},
+		"gob":     GobCodec{},
+		"msgpack": MsgpackCodec{},
+	}
+)
+
+// structField is a field of a struct mapped to a hash field.
+type structField struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	codec     Codec
+}
+
+// HSetStruct sets the fields of the struct v to the hash key, mapped by their redis tags:
+//
+//	type User struct {
+//		Name     string            `redis:"name"`
+//		Email    *string           `redis:"email,omitempty"`
+//		Birthday time.Time         `redis:"birthday"`
+//		Address  Address           `redis:"address,msgpack"`
+//		Extra    map[string]string `redis:"-"`
+//	}
+//
+// Untagged exported fields use their names, fields tagged "-" are skipped.
+// Strings, numbers, booleans, time.Time (RFC 3339) and encoding.TextMarshaler are stored as text,
+// other types are encoded with the codec named in the tag, json, gob or msgpack, json by default.
+// Nil pointers and, with omitempty, zero values are not set.
+func (s *Redis) HSetStruct(key string, v interface{}) error {
+	return s.HSetStructCtx(s.ctx, key, v)
+}
+
+// HSetStructCtx sets the fields of the struct v to the hash key, see HSetStruct.
+func (s *Redis) HSetStructCtx(ctx context.Context, key string, v interface{}) error {
+	val, fields, err := structOf(v)
+	if err != nil {
+		return err
+	}
+
+	values := make([]interface{}, 0, 2*len(fields))
+	for _, field := range fields {
+		fv, ok := fieldByIndex(val, field.index)
+		if !ok || isNilPtr(fv) || field.omitEmpty && fv.IsZero() {
+			continue
+		}
+
+		str, err := field.encode(fv)
+		if err != nil {
+			return err
+		}
+		values = append(values, field.name, str)
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	return s.client.HSet(ctx, s.key(key), values...).Err()
+}
+
+// HSetFields sets only the named fields of the struct v to the hash key, for partial updates.
+// Names are the hash field names, zero values are set and nil pointers delete their fields.
+func (s *Redis) HSetFields(key string, v interface{}, names ...string) error {
+	return s.HSetFieldsCtx(s.ctx, key, v, names...)
+}
+
+// HSetFieldsCtx sets only the named fields of the struct v to the hash key, for partial updates.
+// Names are the hash field names, zero values are set and nil pointers delete their fields.
+func (s *Redis) HSetFieldsCtx(ctx context.Context, key string, v interface{}, names ...string) error {
+	val, fields, err := structOf(v)
+	if err != nil {
+		return err
+	}
+
+	var values []interface{}
+	var deleted []string
+	for _, name := range names {
+		field, ok := findField(fields, name)
+		if !ok {
+			return fmt.Errorf("redis: unknown field %q of %s", name, val.Type())
+		}
+
+		fv, ok := fieldByIndex(val, field.index)
+		if !ok || isNilPtr(fv) {
+			deleted = append(deleted, field.name)
+			continue
+		}
+
+		str, err := field.encode(fv)
+		if err != nil {
+			return err
+		}
+		values = append(values, field.name, str)
+	}
+
+	_, err = s.client.TxPipelined(ctx, func(pipe red.Pipeliner) error {
+		if len(values) > 0 {
+			pipe.HSet(ctx, s.key(key), values...)
+		}
+		if len(deleted) > 0 {
+			pipe.HDel(ctx, s.key(key), deleted...)
+		}
+		return nil
+	})
+
+	return err
+}
+
+// HGetAllStruct gets the hash key into the struct pointed by v, see HSetStruct for the mapping.
+// Fields missing in the hash are left as is, it returns Nil if key doesn't exist.
+func (s *Redis) HGetAllStruct(key string, v interface{}) error {
+	return s.HGetAllStructCtx(s.ctx, key, v)
+}
+
+// HGetAllStructCtx gets the hash key into the struct pointed by v, see HSetStruct for the mapping.
+// Fields missing in the hash are left as is, it returns Nil if key doesn't exist.
+func (s *Redis) HGetAllStructCtx(ctx context.Context, key string, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return ErrNotStruct
+	}
+
+	val, fields, err := structOf(v)
+	if err != nil {
+		return err
+	}
+
+	hash, err := s.HGetAllCtx(ctx, key)
+	if err != nil {
+		return err
+	}
+	if len(hash) == 0 {
+		return Nil
+	}
+
+	for _, field := range fields {
+		str, ok := hash[field.name]
+		if !ok {
+			continue
+		}
+
+		fv, err := allocFieldByIndex(val, field.index)
+		if err == nil {
+			err = field.decode(fv, str)
+		}
+		if err != nil {
+			return fmt.Errorf("redis: field %q: %w", field.name, err)
+		}
+	}
+
+	return nil
+}
+
+// structOf returns the struct value of v, dereferencing pointers, and its fields.
+// Structs passed by value are copied, so that their fields are addressable
+// and encoded with the methods of their pointers, like MarshalText of big.Int.
+func structOf(v interface{}) (reflect.Value, []structField, error) {
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return val, nil, ErrNotStruct
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return val, nil, ErrNotStruct
+	}
+	if !val.CanAddr() {
+		addressable := reflect.New(val.Type()).Elem()
+		addressable.Set(val)
+		val = addressable
+	}
+
+	return val, cachedFields(val.Type()), nil
+}
+
+func cachedFields(t reflect.Type) []structField {
+	if fields, ok := structFields.Load(t); ok {
+		return fields.([]structField)
+	}
+
+	fields := typeFields(t, nil)
+	structFields.Store(t, fields)
+	return fields
+}
+
+// typeFields returns the mapped fields of t, embedded structs without tags are flattened.
+func typeFields(t reflect.Type, index []int) []structField {
+	var fields []structField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, hasTag := f.Tag.Lookup(structTag)
+		if tag == "-" {
+			continue
+		}
+
+		fieldIndex := append(append([]int(nil), index...), i)
+		ft := f.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if f.Anonymous && !hasTag && ft.Kind() == reflect.Struct && ft != timeType {
+			fields = append(fields, typeFields(ft, fieldIndex)...)
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+
+		opts := strings.Split(tag, ",")
+		field := structField{
+			name:  opts[0],
+			index: fieldIndex,
+			codec: JSONCodec{},
+		}
+		if len(field.name) == 0 {
+			field.name = f.Name
+		}
+		for _, opt := range opts[1:] {
+			if opt == "omitempty" {
+				field.omitEmpty = true
+			} else if codec, ok := fieldCodecs[opt]; ok {
+				field.codec = codec
+			}
+		}
+		fields = append(fields, field)
+	}
+
+	return fields
+}
+
+func findField(fields []structField, name string) (structField, bool) {
+	for _, field := range fields {
+		if field.name == name {
+			return field, true
+		}
+	}
+
+	return structField{}, false
+}
+
+// fieldByIndex returns the field of val at index, it's not ok if it's in a nil embedded pointer.
+func fieldByIndex(val reflect.Value, index []int) (reflect.Value, bool) {
+	for i, idx := range index {
+		if i > 0 && val.Kind() == reflect.Ptr {
+			if val.IsNil() {
+				return val, false
+			}
+			val = val.Elem()
+		}
+		val = val.Field(idx)
+	}
+
+	return val, true
+}
+
+// allocFieldByIndex returns the field of val at index, allocating the nil embedded pointers.
+// Like encoding/json, it fails on nil pointers to unexported structs, which can't be set.
+func allocFieldByIndex(val reflect.Value, index []int) (reflect.Value, error) {
+	for i, idx := range index {
+		if i > 0 && val.Kind() == reflect.Ptr {
+			if val.IsNil() {
+				if !val.CanSet() {
+					return val, fmt.Errorf("cannot set embedded pointer to unexported struct %s", val.Type().Elem())
+				}
+				val.Set(reflect.New(val.Type().Elem()))
+			}
+			val = val.Elem()
+		}
+		val = val.Field(idx)
+	}
+
+	return val, nil
+}
+
+// isNilPtr reports whether v is a nil pointer, or a pointer to a nil pointer.
+func isNilPtr(v reflect.Value) bool {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return true
+		}
+		v = v.Elem()
+	}
+
+	return false
+}
+
+func (f structField) encode(v reflect.Value) (string, error) {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", fmt.Errorf("nil pointer %s", v.Type())
+		}
+		v = v.Elem()
+	}
+
+	if v.Type() == timeType {
+		return v.Interface().(time.Time).Format(time.RFC3339Nano), nil
+	}
+	if v.Type().Implements(textMarshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(textMarshalerType) {
+		text, err := v.Addr().Interface().(encoding.TextMarshaler).MarshalText()
+		return string(text), err
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 32), nil
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return string(v.Bytes()), nil
+		}
+	}
+
+	data, err := f.codec.Marshal(v.Interface())
+	return string(data), err
+}
+
+func (f structField) decode(v reflect.Value, str string) error {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		return f.decode(v.Elem(), str)
+	}
+
+	if v.Type() == timeType {
+		t, err := time.Parse(time.RFC3339Nano, str)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(t))
+		return nil
+	}
+	if reflect.PtrTo(v.Type()).Implements(textUnmarshalerType) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(str))
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(str)
+		return nil
+	case reflect.Bool:
+		b, err := strconv.ParseBool(str)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(str, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(str, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(n)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(str, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(n)
+		return nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte(str))
+			return nil
+		}
+	}
+
+	return f.codec.Unmarshal([]byte(str), v.Addr().Interface())
+}
@@ -0,0 +1,213 @@
+package rredis
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type (
+	structAddress struct {
+		City string
+		Zip  string
+	}
+
+	structBase struct {
+		ID int64 `redis:"id"`
+	}
+
+	structUser struct {
+		structBase
+		Name     string         `redis:"name"`
+		Nick     *string        `redis:"nick,omitempty"`
+		Age      uint8          `redis:"age,omitempty"`
+		Score    float64        `redis:"score"`
+		Admin    bool           `redis:"admin"`
+		Birthday time.Time      `redis:"birthday"`
+		Timeout  time.Duration  `redis:"timeout"`
+		Address  *structAddress `redis:"address,msgpack"`
+		Tags     []string       `redis:"tags"`
+		Secret   string         `redis:"-"`
+		Untagged string
+		private  string
+	}
+
+	structBig struct {
+		Amount big.Int  `redis:"amount"`
+		Ref    **string `redis:"ref"`
+	}
+
+	structEmbeddedPtr struct {
+		*structBase
+		Name string `redis:"name"`
+	}
+)
+
+func TestStructFields(t *testing.T) {
+	var names []string
+	for _, field := range cachedFields(reflect.TypeOf(structUser{})) {
+		names = append(names, field.name)
+	}
+
+	want := []string{"id", "name", "nick", "age", "score", "admin", "birthday", "timeout", "address", "tags",
+		"Untagged"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("expected fields %v, got %v", want, names)
+	}
+}
+
+func TestStructRoundTrip(t *testing.T) {
+	nick := "tommy"
+	user := structUser{
+		structBase: structBase{ID: 7},
+		Name:       "tom",
+		Nick:       &nick,
+		Score:      9.5,
+		Admin:      true,
+		Birthday:   time.Date(2000, 1, 2, 3, 4, 5, 6, time.UTC),
+		Timeout:    time.Second,
+		Address:    &structAddress{City: "Paris", Zip: "75001"},
+		Tags:       []string{"a", "b"},
+		Secret:     "hidden",
+		Untagged:   "x",
+	}
+
+	val, fields, err := structOf(&user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hash := make(map[string]string)
+	for _, field := range fields {
+		fv, _ := fieldByIndex(val, field.index)
+		if field.omitEmpty && fv.IsZero() {
+			continue
+		}
+		if hash[field.name], err = field.encode(fv); err != nil {
+			t.Fatalf("%s: %v", field.name, err)
+		}
+	}
+	if _, ok := hash["age"]; ok {
+		t.Fatal("expected the zero age to be omitted")
+	}
+	if hash["birthday"] != "2000-01-02T03:04:05.000000006Z" || hash["tags"] != `["a","b"]` {
+		t.Fatalf("unexpected encoding: %v", hash)
+	}
+
+	var got structUser
+	val, fields, _ = structOf(&got)
+	for _, field := range fields {
+		if str, ok := hash[field.name]; ok {
+			fv, err := allocFieldByIndex(val, field.index)
+			if err != nil {
+				t.Fatalf("%s: %v", field.name, err)
+			}
+			if err = field.decode(fv, str); err != nil {
+				t.Fatalf("%s: %v", field.name, err)
+			}
+		}
+	}
+
+	user.Secret = ""
+	if !reflect.DeepEqual(got, user) {
+		t.Fatalf("expected %+v, got %+v", user, got)
+	}
+}
+
+func TestStructUnexportedEmbeddedPtr(t *testing.T) {
+	var v structEmbeddedPtr
+	val, fields, err := structOf(&v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	field, ok := findField(fields, "id")
+	if !ok {
+		t.Fatal("expected the fields of the embedded struct")
+	}
+	if _, err = allocFieldByIndex(val, field.index); err == nil {
+		t.Fatal("expected an error for the nil unexported embedded pointer")
+	}
+
+	v.structBase = &structBase{}
+	fv, err := allocFieldByIndex(val, field.index)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = field.decode(fv, "7"); err != nil || v.ID != 7 {
+		t.Fatalf("expected the field of the allocated embedded struct set, got %d, %v", v.ID, err)
+	}
+}
+
+func TestStructOfInvalid(t *testing.T) {
+	if _, _, err := structOf("tom"); err != ErrNotStruct {
+		t.Fatalf("expected ErrNotStruct, got %v", err)
+	}
+	if _, _, err := structOf((*structUser)(nil)); err != ErrNotStruct {
+		t.Fatalf("expected ErrNotStruct, got %v", err)
+	}
+}
+
+func TestHSetStructByValue(t *testing.T) {
+	rds, h := newMemoryRedis()
+
+	var v structBig
+	v.Amount.SetInt64(42)
+	var ref *string
+	v.Ref = &ref
+	if err := rds.HSetStruct("order:1", v); err != nil {
+		t.Fatal(err)
+	}
+	if err := rds.HSetStruct("order:2", &v); err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{"order:1", "order:2"} {
+		if amount := h.hashes[key]["amount"]; amount != "42" {
+			t.Fatalf("%s: expected the text of the big.Int, got %q", key, amount)
+		}
+		if _, ok := h.hashes[key]["ref"]; ok {
+			t.Fatalf("%s: expected the nil inner pointer skipped", key)
+		}
+	}
+
+	var got structBig
+	if err := rds.HGetAllStruct("order:1", &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Amount.Int64() != 42 {
+		t.Fatalf("expected 42, got %s", got.Amount.String())
+	}
+}
+
+func TestHSetFields(t *testing.T) {
+	rds, h := newMemoryRedis()
+
+	nick := "tommy"
+	user := structUser{Name: "tom", Nick: &nick, Age: 30, Secret: "hidden"}
+	if err := rds.HSetStruct("user:1", &user); err != nil {
+		t.Fatal(err)
+	}
+
+	user.Name = "tim"
+	user.Nick = nil
+	user.Age = 0
+	if err := rds.HSetFields("user:1", user, "name", "nick", "age"); err != nil {
+		t.Fatal(err)
+	}
+
+	hash := h.hashes["user:1"]
+	if hash["name"] != "tim" || hash["age"] != "0" {
+		t.Fatalf("expected the named fields set, zero values too, got %v", hash)
+	}
+	if _, ok := hash["nick"]; ok {
+		t.Fatalf("expected the nil pointer to delete its field, got %v", hash)
+	}
+	if hash["score"] != "0" || hash["admin"] != "false" {
+		t.Fatalf("expected the other fields left as is, got %v", hash)
+	}
+
+	if err := rds.HSetFields("user:1", user, "unknown"); err == nil {
+		t.Fatal("expected an error for an unknown field")
+	}
+}
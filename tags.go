@@ -0,0 +1,151 @@
+package rredis
+
+import (
+	"context"
+	"time"
+
+	red "github.com/redis/go-redis/v9"
+)
+
+const (
+	// tagKeyPrefix prefixes the sorted sets of tagged keys, the tag is a hash tag
+	// so that each set lives in its own slot in cluster mode.
+	tagKeyPrefix = "rredis:tag:"
+
+	// tagScript adds ARGV[1] to the tag set scored by its expiry ARGV[2], trims the expired members
+	// with ARGV[3] as now, and expires the set with its last member, unless that one never expires.
+	tagScript = `redis.call("ZADD", KEYS[1], ARGV[2], ARGV[1])
+redis.call("ZREMRANGEBYSCORE", KEYS[1], "-inf", ARGV[3])
+local last = redis.call("ZRANGE", KEYS[1], -1, -1, "WITHSCORES")
+if #last > 0 and last[2] == "inf" then
+	redis.call("PERSIST", KEYS[1])
+elseif #last > 0 then
+	redis.call("PEXPIREAT", KEYS[1], last[2])
+end
+return 1`
+	// invalidateTagsScript deletes the members of the tag sets, and the sets themselves.
+	invalidateTagsScript = `local deleted = 0
+for _, tag in ipairs(KEYS) do
+	local members = redis.call("ZRANGE", tag, 0, -1)
+	for i = 1, #members, 1000 do
+		deleted = deleted + redis.call("DEL", unpack(members, i, math.min(i + 999, #members)))
+	end
+	redis.call("DEL", tag)
+end
+return deleted`
+	// popTagScript deletes the tag set and returns its members.
+	popTagScript = `local members = redis.call("ZRANGE", KEYS[1], 0, -1)
+redis.call("DEL", KEYS[1])
+return members`
+)
+
+// SetWithTags sets key to value for seconds, and records it in the sets of tags,
+// so that InvalidateTags deletes it. Expired keys are trimmed from the sets when tagging,
+// and the sets expire with their last key. Keys set with seconds <= 0 never expire.
+func (s *Redis) SetWithTags(key string, value interface{}, seconds int64, tags ...string) error {
+	return s.SetWithTagsCtx(s.ctx, key, value, seconds, tags...)
+}
+
+// SetWithTagsCtx sets key to value for seconds, and records it in the sets of tags,
+// so that InvalidateTags deletes it. Expired keys are trimmed from the sets when tagging,
+// and the sets expire with their last key. Keys set with seconds <= 0 never expire.
+func (s *Redis) SetWithTagsCtx(ctx context.Context, key string, value interface{}, seconds int64,
+	tags ...string) error {
+	expire := time.Duration(seconds) * time.Second
+	now := time.Now()
+	expireAt := tagScore(seconds, now)
+
+	_, err := s.client.TxPipelined(ctx, func(pipe red.Pipeliner) error {
+		pipe.Set(ctx, s.key(key), value, expire)
+		for _, tag := range tags {
+			pipe.Eval(ctx, tagScript, []string{s.tagKey(tag)}, s.key(key), expireAt, now.UnixMilli())
+		}
+		return nil
+	})
+
+	return err
+}
+
+// InvalidateTags deletes every key tagged with tags, and returns the number of deleted keys.
+// It's atomic on a single node, in cluster mode each tag set is read and deleted atomically,
+// then its keys are deleted with one command per slot.
+func (s *Redis) InvalidateTags(tags ...string) (int64, error) {
+	return s.InvalidateTagsCtx(s.ctx, tags...)
+}
+
+// InvalidateTagsCtx deletes every key tagged with tags, and returns the number of deleted keys.
+// It's atomic on a single node, in cluster mode each tag set is read and deleted atomically,
+// then its keys are deleted with one command per slot.
+func (s *Redis) InvalidateTagsCtx(ctx context.Context, tags ...string) (int64, error) {
+	if len(tags) == 0 {
+		return 0, nil
+	}
+
+	tagKeys := make([]string, len(tags))
+	for i, tag := range tags {
+		tagKeys[i] = s.tagKey(tag)
+	}
+
+	if _, ok := s.client.(*red.ClusterClient); !ok {
+		return s.client.Eval(ctx, invalidateTagsScript, tagKeys).Int64()
+	}
+
+	return s.invalidateTagsBySlot(ctx, tagKeys)
+}
+
+// invalidateTagsBySlot pops the members of each tag set atomically,
+// then deletes them with one command per slot, for the keys of a set may live in any slot.
+func (s *Redis) invalidateTagsBySlot(ctx context.Context, tagKeys []string) (int64, error) {
+	cmds, err := s.client.Pipelined(ctx, func(pipe red.Pipeliner) error {
+		for _, key := range tagKeys {
+			pipe.Eval(ctx, popTagScript, []string{key})
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var members []string
+	for _, cmd := range cmds {
+		keys, err := cmd.(*red.Cmd).StringSlice()
+		if err != nil {
+			return 0, err
+		}
+		members = append(members, keys...)
+	}
+	if len(members) == 0 {
+		return 0, nil
+	}
+
+	cmds, err = s.client.Pipelined(ctx, func(pipe red.Pipeliner) error {
+		for _, keys := range groupBySlot(members) {
+			pipe.Del(ctx, keys...)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var deleted int64
+	for _, cmd := range cmds {
+		deleted += cmd.(*red.IntCmd).Val()
+	}
+
+	return deleted, nil
+}
+
+// tagScore returns the score of a key tagged at now for seconds, its expiry in milliseconds,
+// or +inf if it never expires, so that it's never trimmed.
+func tagScore(seconds int64, now time.Time) interface{} {
+	if seconds <= 0 {
+		return "+inf"
+	}
+
+	return now.Add(time.Duration(seconds) * time.Second).UnixMilli()
+}
+
+func (s *Redis) tagKey(tag string) string {
+	return s.key(tagKeyPrefix + "{" + tag + "}")
+}
@@ -0,0 +1,127 @@
+package rredis
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	red "github.com/redis/go-redis/v9"
+)
+
+func TestTagScore(t *testing.T) {
+	now := time.Now()
+	if score := tagScore(60, now); score != now.Add(time.Minute).UnixMilli() {
+		t.Fatalf("expected the expiry as score, got %v", score)
+	}
+
+	// keys without expiration must outlive the trimming of the expired members.
+	for _, seconds := range []int64{0, -1} {
+		if score := tagScore(seconds, now); score != "+inf" {
+			t.Fatalf("expected +inf for %d seconds, got %v", seconds, score)
+		}
+	}
+}
+
+func TestTagKey(t *testing.T) {
+	rds := &Redis{prefix: "app:"}
+	if key := rds.tagKey("user:1"); key != "app:rredis:tag:{user:1}" {
+		t.Fatalf("unexpected tag key %q", key)
+	}
+	if keySlot(rds.tagKey("user:1")) != keySlot("user:1") {
+		t.Fatal("expected the tag set in the slot of its tag")
+	}
+}
+
+func TestSetWithTags(t *testing.T) {
+	rds, h := newMemoryRedis()
+	users := rds.tagKey("users")
+
+	// a member expired a second ago is trimmed by the next tagging.
+	h.zsets[users] = map[string]float64{"user:0": float64(time.Now().Add(-time.Second).UnixMilli())}
+	if err := rds.SetWithTags("user:1", "ann", 60, "users", "team:1"); err != nil {
+		t.Fatal(err)
+	}
+	if h.data["user:1"] != "ann" || h.expires["user:1"] != time.Minute {
+		t.Fatalf("expected user:1 set for a minute, got %q for %s", h.data["user:1"], h.expires["user:1"])
+	}
+	if _, ok := h.zsets[users]["user:0"]; ok {
+		t.Fatal("expected the expired member trimmed")
+	}
+	for _, tag := range []string{"users", "team:1"} {
+		if _, ok := h.zsets[rds.tagKey(tag)]["user:1"]; !ok {
+			t.Fatalf("expected user:1 in the set of %s", tag)
+		}
+	}
+	if expire := h.expires[users]; expire <= 59*time.Second || expire > time.Minute {
+		t.Fatalf("expected the tag set to expire with its last member, got %s", expire)
+	}
+
+	if err := rds.SetWithTags("user:2", "bob", 0, "users"); err != nil {
+		t.Fatal(err)
+	}
+	if score := h.zsets[users]["user:2"]; !math.IsInf(score, 1) {
+		t.Fatalf("expected a key without expiration scored +inf, got %v", score)
+	}
+	if _, ok := h.expires[users]; ok {
+		t.Fatal("expected the tag set persisted with a member that never expires")
+	}
+}
+
+func TestInvalidateTags(t *testing.T) {
+	rds, h := newMemoryRedis()
+	for key, tag := range map[string]string{"user:1": "users", "user:2": "users", "team:1": "teams"} {
+		if err := rds.SetWithTags(key, key, 60, tag); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h.data["other"] = "kept"
+
+	if n, err := rds.InvalidateTags(); n != 0 || err != nil {
+		t.Fatalf("expected nothing invalidated without tags, got %d, %v", n, err)
+	}
+	n, err := rds.InvalidateTags("users", "missing")
+	if err != nil || n != 2 {
+		t.Fatalf("expected 2 keys deleted, got %d, %v", n, err)
+	}
+	if _, ok := h.data["user:1"]; ok {
+		t.Fatal("expected the tagged keys deleted")
+	}
+	if _, ok := h.zsets[rds.tagKey("users")]; ok {
+		t.Fatal("expected the tag set deleted")
+	}
+	if h.data["team:1"] != "team:1" || h.data["other"] != "kept" {
+		t.Fatal("expected the keys of other tags kept")
+	}
+}
+
+func TestInvalidateTagsBySlot(t *testing.T) {
+	rds, h := newMemoryRedis()
+	keys := []string{"user:1", "user:2", "user:3", "team:1"}
+	for _, key := range keys {
+		if err := rds.SetWithTags(key, key, 60, "users", "all"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var dels int
+	h.before = func(cmd red.Cmder) {
+		if cmd.Name() == "del" {
+			dels++
+		}
+	}
+	n, err := rds.invalidateTagsBySlot(context.Background(), []string{rds.tagKey("users"), rds.tagKey("all")})
+	if err != nil || n != int64(len(keys)) {
+		t.Fatalf("expected %d keys deleted, got %d, %v", len(keys), n, err)
+	}
+	if dels != len(groupBySlot(keys)) {
+		t.Fatalf("expected one DEL per slot, got %d", dels)
+	}
+	if len(h.data) != 0 || len(h.zsets) != 0 {
+		t.Fatalf("expected the keys and the tag sets deleted, got %q and %v", h.data, h.zsets)
+	}
+
+	if n, err = rds.invalidateTagsBySlot(context.Background(), []string{rds.tagKey("users")}); n != 0 || err != nil {
+		t.Fatalf("expected nothing deleted from empty tags, got %d, %v", n, err)
+	}
+}
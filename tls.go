@@ -0,0 +1,69 @@
+package rredis
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrInvalidCA is an error that indicates no certificate could be loaded from the CA bundle.
+var ErrInvalidCA = errors.New("no certificates found in CA bundle")
+
+// newTLSConfig builds the tls config of opt, it returns nil when tls is disabled.
+// Server certificates are verified unless TLSInsecureSkipVerify is set,
+// the server name defaults to the host of the dialed address.
+func newTLSConfig(opt *Option) (*tls.Config, error) {
+	if !opt.Tls {
+		return nil, nil
+	}
+
+	conf := &tls.Config{
+		ServerName:         opt.TLSServerName,
+		MinVersion:         opt.TLSMinVersion,
+		InsecureSkipVerify: opt.TLSInsecureSkipVerify,
+	}
+	if conf.MinVersion == 0 {
+		conf.MinVersion = tls.VersionTLS12
+	}
+
+	if len(opt.TLSCAFile) > 0 {
+		pem, err := os.ReadFile(opt.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("read tls ca file: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidCA, opt.TLSCAFile)
+		}
+		conf.RootCAs = pool
+	}
+
+	if len(opt.TLSCertFile) > 0 || len(opt.TLSKeyFile) > 0 {
+		cert, err := tls.LoadX509KeyPair(opt.TLSCertFile, opt.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("load tls client certificate: %w", err)
+		}
+		conf.Certificates = []tls.Certificate{cert}
+	}
+
+	return conf, nil
+}
+
+// parseTLSVersion parses versions like 1.2 or 1.3.
+func parseTLSVersion(val string) (uint16, bool) {
+	switch val {
+	case "1.0":
+		return tls.VersionTLS10, true
+	case "1.1":
+		return tls.VersionTLS11, true
+	case "1.2":
+		return tls.VersionTLS12, true
+	case "1.3":
+		return tls.VersionTLS13, true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,145 @@
+package rredis
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeTestCerts writes a self-signed CA and a client certificate signed by it to dir,
+// it returns the CA file, the client certificate and key files, and the client certificate.
+func writeTestCerts(t *testing.T, dir string) (caFile, certFile, keyFile string, cert *x509.Certificate) {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "client"},
+		DNSNames:     []string{"redis.test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth, x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cert, err = x509.ParseCertificate(der); err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caFile = filepath.Join(dir, "ca.pem")
+	certFile = filepath.Join(dir, "client.pem")
+	keyFile = filepath.Join(dir, "client.key")
+	writePEM(t, caFile, "CERTIFICATE", caDER)
+	writePEM(t, certFile, "CERTIFICATE", der)
+	writePEM(t, keyFile, "EC PRIVATE KEY", keyDER)
+
+	return caFile, certFile, keyFile, cert
+}
+
+func writePEM(t *testing.T, file, typ string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: typ, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewTLSConfig(t *testing.T) {
+	caFile, certFile, keyFile, cert := writeTestCerts(t, t.TempDir())
+
+	conf, err := newTLSConfig(&Option{Tls: true, TLSCAFile: caFile, TLSCertFile: certFile,
+		TLSKeyFile: keyFile, TLSServerName: "redis.test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.MinVersion != tls.VersionTLS12 || conf.ServerName != "redis.test" || conf.InsecureSkipVerify {
+		t.Fatalf("unexpected tls config %+v", conf)
+	}
+	if _, err = cert.Verify(x509.VerifyOptions{Roots: conf.RootCAs, DNSName: "redis.test"}); err != nil {
+		t.Fatalf("expected the CA file in RootCAs, got %v", err)
+	}
+	if len(conf.Certificates) != 1 || string(conf.Certificates[0].Certificate[0]) != string(cert.Raw) {
+		t.Fatal("expected the client certificate in Certificates")
+	}
+
+	conf, err = newTLSConfig(&Option{Tls: true, TLSMinVersion: tls.VersionTLS13})
+	if err != nil || conf.MinVersion != tls.VersionTLS13 || conf.RootCAs != nil || len(conf.Certificates) != 0 {
+		t.Fatalf("unexpected tls config %+v, %v", conf, err)
+	}
+
+	if conf, err = newTLSConfig(&Option{TLSCAFile: caFile}); conf != nil || err != nil {
+		t.Fatalf("expected no tls config without Tls, got %+v, %v", conf, err)
+	}
+}
+
+func TestNewTLSConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	caFile, certFile, _, _ := writeTestCerts(t, dir)
+	invalid := filepath.Join(dir, "invalid.pem")
+	if err := os.WriteFile(invalid, []byte("not a certificate"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := newTLSConfig(&Option{Tls: true, TLSCAFile: invalid}); !errors.Is(err, ErrInvalidCA) {
+		t.Fatalf("expected ErrInvalidCA, got %v", err)
+	}
+	if _, err := newTLSConfig(&Option{Tls: true, TLSCAFile: filepath.Join(dir, "missing.pem")}); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected the missing CA file reported, got %v", err)
+	}
+	// the CA certificate isn't the key of the client certificate.
+	if _, err := newTLSConfig(&Option{Tls: true, TLSCertFile: certFile, TLSKeyFile: caFile}); err == nil {
+		t.Fatal("expected an error for an invalid client key")
+	}
+
+	opt := &Option{Tls: true, TLSCAFile: invalid}
+	if _, err := NewRedis("127.0.0.1:6379", opt); !errors.Is(err, ErrInvalidCA) {
+		t.Fatalf("expected NewRedis to return ErrInvalidCA, got %v", err)
+	}
+	defer func() {
+		if err, ok := recover().(error); !ok || !errors.Is(err, ErrInvalidCA) {
+			t.Fatalf("expected NewClient to panic with ErrInvalidCA, got %v", err)
+		}
+	}()
+	NewClient("127.0.0.1:6379", opt)
+}
@@ -0,0 +1,152 @@
+package rredis
+
+import (
+	"context"
+	red "github.com/redis/go-redis/v9"
+	"net"
+	"strings"
+)
+
+const (
+	// AttrDBSystem is the attribute key of the database system, always redis.
+	AttrDBSystem = "db.system"
+	// AttrDBStatement is the attribute key of the sanitized command, values are replaced with ?.
+	AttrDBStatement = "db.statement"
+	// AttrDBKey is the attribute key of the first key of the command.
+	AttrDBKey = "db.redis.key"
+	// AttrServerAddress is the attribute key of the address of the redis node.
+	AttrServerAddress = "server.address"
+
+	spanPrefix   = "redis."
+	spanPipeline = "redis.pipeline"
+)
+
+type (
+	// Tracer starts spans for redis commands, pipelines and scripts.
+	// An OpenTelemetry tracer can be plugged in with a small adapter.
+	Tracer interface {
+		// Start starts a span as a child of the span in ctx,
+		// the returned ctx carries the new span.
+		Start(ctx context.Context, name string) (context.Context, Span)
+	}
+
+	// Span is a span started by Tracer.
+	Span interface {
+		SetAttributes(attrs ...Attribute)
+		RecordError(err error)
+		End()
+	}
+
+	// An Attribute is a key/value pair set on spans.
+	Attribute struct {
+		Key   string
+		Value string
+	}
+)
+
+type tracingHook struct {
+	tracer Tracer
+	addr   string
+}
+
+// installTracing adds the tracing hook to client, for redis cluster the hook is
+// added to each node so that spans carry the address of the node.
+func installTracing(client red.UniversalClient, tracer Tracer) {
+	switch c := client.(type) {
+	case *red.ClusterClient:
+		c.OnNewNode(func(node *red.Client) {
+			node.AddHook(newTracingHook(tracer, node.Options().Addr))
+		})
+	case *red.Client:
+		c.AddHook(newTracingHook(tracer, c.Options().Addr))
+	default:
+		client.AddHook(newTracingHook(tracer, ""))
+	}
+}
+
+func newTracingHook(tracer Tracer, addr string) *tracingHook {
+	// failover clients don't have a fixed address
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = ""
+	}
+
+	return &tracingHook{
+		tracer: tracer,
+		addr:   addr,
+	}
+}
+
+func (h *tracingHook) DialHook(next red.DialHook) red.DialHook {
+	return next
+}
+
+func (h *tracingHook) ProcessHook(next red.ProcessHook) red.ProcessHook {
+	return func(ctx context.Context, cmd red.Cmder) error {
+		ctx, span := h.tracer.Start(ctx, spanPrefix+cmd.Name())
+		defer span.End()
+
+		attrs := h.attributes(sanitize(cmd))
+		if key := cmdKey(cmd); len(key) > 0 {
+			attrs = append(attrs, Attribute{Key: AttrDBKey, Value: key})
+		}
+		span.SetAttributes(attrs...)
+
+		err := next(ctx, cmd)
+		if err != nil && err != Nil {
+			span.RecordError(err)
+		}
+
+		return err
+	}
+}
+
+func (h *tracingHook) ProcessPipelineHook(next red.ProcessPipelineHook) red.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []red.Cmder) error {
+		ctx, span := h.tracer.Start(ctx, spanPipeline)
+		defer span.End()
+
+		statements := make([]string, len(cmds))
+		for i, cmd := range cmds {
+			statements[i] = sanitize(cmd)
+		}
+		span.SetAttributes(h.attributes(strings.Join(statements, "\n"))...)
+
+		err := next(ctx, cmds)
+		if err != nil && err != Nil {
+			span.RecordError(err)
+		}
+
+		return err
+	}
+}
+
+func (h *tracingHook) attributes(statement string) []Attribute {
+	attrs := []Attribute{
+		{Key: AttrDBSystem, Value: "redis"},
+		{Key: AttrDBStatement, Value: statement},
+	}
+	if len(h.addr) > 0 {
+		attrs = append(attrs, Attribute{Key: AttrServerAddress, Value: h.addr})
+	}
+
+	return attrs
+}
+
+// sanitize returns the command with its key, other args are replaced with ?.
+func sanitize(cmd red.Cmder) string {
+	args := cmd.Args()
+	pos := cmdKeyPos(cmd)
+
+	var b strings.Builder
+	b.WriteString(cmd.Name())
+	for i := 1; i < len(args); i++ {
+		b.WriteByte(' ')
+		if i == pos {
+			b.WriteString(Repr(args[i]))
+		} else {
+			b.WriteByte('?')
+		}
+	}
+
+	return b.String()
+}
@@ -0,0 +1,95 @@
+package rredis
+
+import (
+	"context"
+	"errors"
+	red "github.com/redis/go-redis/v9"
+	"sync"
+	"testing"
+)
+
+type (
+	spanKey struct{}
+
+	memoryTracer struct {
+		lock  sync.Mutex
+		spans []*memorySpan
+	}
+
+	memorySpan struct {
+		name   string
+		parent *memorySpan
+		attrs  map[string]string
+		err    error
+		ended  bool
+	}
+)
+
+func (t *memoryTracer) Start(ctx context.Context, name string) (context.Context, Span) {
+	parent, _ := ctx.Value(spanKey{}).(*memorySpan)
+	span := &memorySpan{name: name, parent: parent, attrs: make(map[string]string)}
+
+	t.lock.Lock()
+	t.spans = append(t.spans, span)
+	t.lock.Unlock()
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (s *memorySpan) SetAttributes(attrs ...Attribute) {
+	for _, attr := range attrs {
+		s.attrs[attr.Key] = attr.Value
+	}
+}
+
+func (s *memorySpan) RecordError(err error) {
+	s.err = err
+}
+
+func (s *memorySpan) End() {
+	s.ended = true
+}
+
+func TestTracingHook(t *testing.T) {
+	tracer := new(memoryTracer)
+	hook := newTracingHook(tracer, "127.0.0.1:6379")
+	root := &memorySpan{name: "root"}
+	ctx := context.WithValue(context.Background(), spanKey{}, root)
+
+	process := hook.ProcessHook(func(ctx context.Context, cmd red.Cmder) error {
+		if ctx.Value(spanKey{}) == root {
+			t.Error("expected the command span in ctx")
+		}
+		return nil
+	})
+	if err := process(ctx, red.NewStatusCmd(ctx, "set", "user:1", "secret")); err != nil {
+		t.Fatal(err)
+	}
+	processEval := hook.ProcessHook(func(ctx context.Context, cmd red.Cmder) error {
+		return errors.New("NOSCRIPT")
+	})
+	processEval(ctx, red.NewCmd(ctx, "evalsha", "abc", 1, "lock:1", "token"))
+	pipeline := hook.ProcessPipelineHook(func(ctx context.Context, cmds []red.Cmder) error {
+		return Nil
+	})
+	pipeline(ctx, []red.Cmder{red.NewStringCmd(ctx, "get", "a"), red.NewIntCmd(ctx, "incr", "b")})
+
+	if len(tracer.spans) != 3 {
+		t.Fatalf("expected 3 spans, got %d", len(tracer.spans))
+	}
+
+	set, eval, pipe := tracer.spans[0], tracer.spans[1], tracer.spans[2]
+	if set.name != "redis.set" || set.parent != root || !set.ended || set.err != nil {
+		t.Errorf("unexpected span %+v", set)
+	}
+	if set.attrs[AttrDBStatement] != "set user:1 ?" || set.attrs[AttrDBKey] != "user:1" ||
+		set.attrs[AttrDBSystem] != "redis" || set.attrs[AttrServerAddress] != "127.0.0.1:6379" {
+		t.Errorf("unexpected attributes %v", set.attrs)
+	}
+	if eval.attrs[AttrDBStatement] != "evalsha ? ? lock:1 ?" || eval.err == nil {
+		t.Errorf("unexpected script span %+v", eval)
+	}
+	if pipe.name != "redis.pipeline" || pipe.attrs[AttrDBStatement] != "get a\nincr b" || pipe.err != nil {
+		t.Errorf("unexpected pipeline span %+v", pipe)
+	}
+}
@@ -0,0 +1,398 @@
+package rredis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	red "github.com/redis/go-redis/v9"
+)
+
+const (
+	defaultTrackingExpire = time.Minute
+	invalidationChannel   = "__redis__:invalidate"
+)
+
+// ErrTrackingCluster is an error that indicates client tracking used in cluster mode.
+var ErrTrackingCluster = errors.New("client tracking is not supported in cluster mode")
+
+// tracker keeps the results of Get, MGet and HGetAll in memory, with CLIENT TRACKING
+// the server pushes the invalidations of keys read through it to a subscribed connection.
+//
+// Reads go through a dedicated client whose connections redirect their invalidations
+// to the subscriber, it's replaced whenever the subscriber reconnects with a new client id.
+type tracker struct {
+	base      red.UniversalClient
+	newClient func(onConnect func(ctx context.Context, cn *red.Conn) error) *red.Client
+	args      []interface{}
+	// prefixes are the prefixes of the keys invalidated in broadcast mode, other keys aren't cached.
+	prefixes []string
+	cache    *lruCache[interface{}]
+	sub      *red.Client
+	pubsub   *red.PubSub
+	done     chan struct{}
+
+	// lock orders the caching of read values with the invalidations,
+	// seq is increased by every invalidation, values read before it are dropped.
+	lock   sync.Mutex
+	seq    uint64
+	client *trackedClient
+}
+
+// trackedClient is a read client, closed once its reads are done after it's replaced.
+type trackedClient struct {
+	*red.Client
+	reads sync.WaitGroup
+}
+
+// newTracker returns a tracker of the keys read through clients built by newClient,
+// reads fall back to base until the subscriber is connected.
+func newTracker(base red.UniversalClient, rdc *Option,
+	newClient func(onConnect func(ctx context.Context, cn *red.Conn) error) *red.Client) *tracker {
+	t := &tracker{
+		base:      base,
+		newClient: newClient,
+		args:      trackingArgs(rdc),
+		prefixes:  broadcastPrefixes(rdc),
+		cache:     newLruCache[interface{}](rdc.TrackingSize, rdc.TrackingExpire),
+		done:      make(chan struct{}),
+	}
+	t.sub = newClient(func(ctx context.Context, cn *red.Conn) error {
+		id, err := cn.ClientID(ctx).Result()
+		if err != nil {
+			return err
+		}
+
+		t.redirect(id)
+		return nil
+	})
+	t.pubsub = t.sub.Subscribe(context.Background(), invalidationChannel)
+	go t.listen()
+
+	return t
+}
+
+func trackingArgs(rdc *Option) []interface{} {
+	if !rdc.TrackingBroadcast {
+		return nil
+	}
+
+	args := []interface{}{"bcast"}
+	for _, prefix := range broadcastPrefixes(rdc) {
+		args = append(args, "prefix", prefix)
+	}
+
+	return args
+}
+
+// broadcastPrefixes returns the prefixed TrackingPrefixes in broadcast mode, nil if every key is tracked.
+func broadcastPrefixes(rdc *Option) []string {
+	if !rdc.TrackingBroadcast {
+		return nil
+	}
+
+	prefixes := rdc.TrackingPrefixes
+	if len(prefixes) == 0 && len(rdc.KeyPrefix) > 0 {
+		prefixes = []string{""}
+	}
+
+	ret := make([]string, len(prefixes))
+	for i, prefix := range prefixes {
+		ret[i] = rdc.KeyPrefix + prefix
+	}
+
+	return ret
+}
+
+// redirect replaces the read client with one redirecting its invalidations to the subscriber id.
+// Values cached before are flushed, their invalidations may have been lost.
+// The replaced client is closed once its reads are done.
+func (t *tracker) redirect(id int64) {
+	args := append([]interface{}{"client", "tracking", "on", "redirect", id}, t.args...)
+	client := &trackedClient{
+		Client: t.newClient(func(ctx context.Context, cn *red.Conn) error {
+			cmd := red.NewStatusCmd(ctx, args...)
+			_ = cn.Process(ctx, cmd)
+			return cmd.Err()
+		}),
+	}
+	// the server forgets the keys read on a connection when it's closed, by the pool too.
+	client.AddHook(connCloseHook{onClose: func() {
+		t.lock.Lock()
+		defer t.lock.Unlock()
+
+		if t.client == client {
+			t.seq++
+			t.cache.Flush()
+		}
+	}})
+
+	t.lock.Lock()
+	old := t.client
+	t.client = client
+	t.seq++
+	t.cache.Flush()
+	t.lock.Unlock()
+
+	if old != nil {
+		go func() {
+			old.reads.Wait()
+			old.Close()
+		}()
+	}
+}
+
+// listen drops the invalidated keys until the subscriber is closed.
+func (t *tracker) listen() {
+	defer close(t.done)
+
+	for {
+		msg, err := t.pubsub.Receive(context.Background())
+		if errors.Is(err, red.ErrClosed) {
+			return
+		}
+		// the null payload of a flush of the server isn't parsed by go-redis and lands here too.
+		if err != nil {
+			t.flush()
+			if strings.HasPrefix(err.Error(), "redis: unsupported pubsub message payload") {
+				continue
+			}
+			time.Sleep(resubscribeBackoff)
+			continue
+		}
+
+		switch m := msg.(type) {
+		case *red.Subscription:
+			t.flush()
+		case *red.Message:
+			if len(m.PayloadSlice) > 0 {
+				t.invalidate(m.PayloadSlice...)
+			} else {
+				t.invalidate(m.Payload)
+			}
+		}
+	}
+}
+
+func (t *tracker) invalidate(keys ...string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.seq++
+	t.cache.Del(keys...)
+}
+
+func (t *tracker) flush() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.seq++
+	t.cache.Flush()
+}
+
+// current returns the read client and the invalidation sequence, before reading,
+// done must be called after reading.
+func (t *tracker) current() (client *red.Client, seq uint64, done func()) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.client == nil {
+		return nil, t.seq, func() {}
+	}
+
+	tc := t.client
+	tc.reads.Add(1)
+	return tc.Client, t.seq, tc.reads.Done
+}
+
+// store caches the value of key read at seq, unless it's been invalidated since
+// or the server doesn't send its invalidations.
+func (t *tracker) store(seq uint64, key string, val interface{}) {
+	if !t.tracks(key) {
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.seq == seq {
+		t.cache.Set(key, val)
+	}
+}
+
+// tracks reports whether the server sends the invalidations of key.
+func (t *tracker) tracks(key string) bool {
+	if len(t.prefixes) == 0 {
+		return true
+	}
+
+	for _, prefix := range t.prefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *tracker) get(ctx context.Context, key string) (string, error) {
+	if val, ok := t.cache.Get(key); ok {
+		if str, ok := val.(string); ok {
+			return str, nil
+		}
+	}
+
+	client, seq, done := t.current()
+	defer done()
+	var cmd *red.StringCmd
+	if client == nil {
+		cmd = t.base.Get(ctx, key)
+	} else {
+		cmd = client.Get(ctx, key)
+	}
+
+	val, err := cmd.Result()
+	if err == red.Nil {
+		val, err = "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	if client != nil {
+		t.store(seq, key, val)
+	}
+	return val, nil
+}
+
+func (t *tracker) mget(ctx context.Context, keys []string) ([]string, error) {
+	vals := make([]string, len(keys))
+	var missing []int
+	for i, key := range keys {
+		if val, ok := t.cache.Get(key); ok {
+			if str, ok := val.(string); ok {
+				vals[i] = str
+				continue
+			}
+		}
+		missing = append(missing, i)
+	}
+	if len(missing) == 0 {
+		return vals, nil
+	}
+
+	remote := make([]string, len(missing))
+	for i, idx := range missing {
+		remote[i] = keys[idx]
+	}
+
+	client, seq, done := t.current()
+	defer done()
+	var cmd *red.SliceCmd
+	if client == nil {
+		cmd = t.base.MGet(ctx, remote...)
+	} else {
+		cmd = client.MGet(ctx, remote...)
+	}
+
+	v, err := cmd.Result()
+	if err != nil {
+		return nil, err
+	}
+	for i, val := range toStrings(v) {
+		vals[missing[i]] = val
+		if client != nil {
+			t.store(seq, remote[i], val)
+		}
+	}
+
+	return vals, nil
+}
+
+func (t *tracker) hgetall(ctx context.Context, key string) (map[string]string, error) {
+	if val, ok := t.cache.Get(key); ok {
+		if m, ok := val.(map[string]string); ok {
+			return copyMap(m), nil
+		}
+	}
+
+	client, seq, done := t.current()
+	defer done()
+	if client == nil {
+		return t.base.HGetAll(ctx, key).Result()
+	}
+
+	val, err := client.HGetAll(ctx, key).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	t.store(seq, key, copyMap(val))
+	return val, nil
+}
+
+func (t *tracker) close() error {
+	err := t.pubsub.Close()
+	<-t.done
+	t.lock.Lock()
+	client := t.client
+	t.client = nil
+	t.lock.Unlock()
+
+	if client != nil {
+		client.reads.Wait()
+		client.Close()
+	}
+	if e := t.sub.Close(); err == nil {
+		err = e
+	}
+
+	return err
+}
+
+// connCloseHook calls onClose when a connection of the client is closed.
+type connCloseHook struct {
+	onClose func()
+}
+
+func (h connCloseHook) DialHook(next red.DialHook) red.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := next(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+
+		return &closeNotifyConn{Conn: conn, onClose: h.onClose}, nil
+	}
+}
+
+func (h connCloseHook) ProcessHook(next red.ProcessHook) red.ProcessHook {
+	return next
+}
+
+func (h connCloseHook) ProcessPipelineHook(next red.ProcessPipelineHook) red.ProcessPipelineHook {
+	return next
+}
+
+type closeNotifyConn struct {
+	net.Conn
+	once    sync.Once
+	onClose func()
+}
+
+func (c *closeNotifyConn) Close() error {
+	c.once.Do(c.onClose)
+	return c.Conn.Close()
+}
+
+func copyMap(m map[string]string) map[string]string {
+	ret := make(map[string]string, len(m))
+	for k, v := range m {
+		ret[k] = v
+	}
+
+	return ret
+}
@@ -0,0 +1,144 @@
+package rredis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	red "github.com/redis/go-redis/v9"
+)
+
+func TestTrackingArgs(t *testing.T) {
+	tests := []struct {
+		name string
+		opt  *Option
+		want []interface{}
+	}{
+		{
+			name: "default",
+			opt:  &Option{},
+		},
+		{
+			name: "broadcast",
+			opt:  &Option{TrackingBroadcast: true},
+			want: []interface{}{"bcast"},
+		},
+		{
+			name: "prefixes",
+			opt:  &Option{TrackingBroadcast: true, TrackingPrefixes: []string{"user:", "order:"}},
+			want: []interface{}{"bcast", "prefix", "user:", "prefix", "order:"},
+		},
+		{
+			name: "key prefix",
+			opt:  &Option{TrackingBroadcast: true, KeyPrefix: "app:"},
+			want: []interface{}{"bcast", "prefix", "app:"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := trackingArgs(test.opt); !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("expected %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestTrackingStore(t *testing.T) {
+	tr := &tracker{
+		prefixes: broadcastPrefixes(&Option{TrackingBroadcast: true, TrackingPrefixes: []string{"user:"}, KeyPrefix: "app:"}),
+		cache:    newLruCache[interface{}](10, time.Minute),
+	}
+
+	tr.store(0, "app:user:1", "alice")
+	tr.store(0, "app:order:1", "book")
+	tr.store(0, "user:2", "bob")
+	if _, ok := tr.cache.Get("app:user:1"); !ok {
+		t.Fatal("expected the key under a tracked prefix cached")
+	}
+	for _, key := range []string{"app:order:1", "user:2"} {
+		if _, ok := tr.cache.Get(key); ok {
+			t.Fatalf("expected %q out of the tracked prefixes not cached", key)
+		}
+	}
+
+	tr.store(1, "app:user:3", "carol")
+	if _, ok := tr.cache.Get("app:user:3"); ok {
+		t.Fatal("expected the value read before an invalidation not cached")
+	}
+
+	tr = &tracker{cache: newLruCache[interface{}](10, time.Minute)}
+	tr.store(0, "order:1", "book")
+	if _, ok := tr.cache.Get("order:1"); !ok {
+		t.Fatal("expected every key cached without prefixes")
+	}
+}
+
+func TestTrackingRedirect(t *testing.T) {
+	tr := &tracker{
+		newClient: func(onConnect func(ctx context.Context, cn *red.Conn) error) *red.Client {
+			return red.NewClient(&red.Options{Addr: "127.0.0.1:1", MaxRetries: -1, OnConnect: onConnect})
+		},
+		cache: newLruCache[interface{}](10, time.Minute),
+	}
+	defer func() {
+		tr.client.Close()
+	}()
+
+	tr.redirect(1)
+	old, seq, done := tr.current()
+	tr.cache.Set("user:1", "ann")
+
+	tr.redirect(2)
+	if _, ok := tr.cache.Get("user:1"); ok {
+		t.Fatal("expected the cache flushed on redirect")
+	}
+	if _, next, release := tr.current(); next == seq {
+		t.Fatal("expected the values read before the redirect dropped")
+	} else {
+		release()
+	}
+
+	// the replaced client serves the reads in flight.
+	if err := old.Ping(context.Background()).Err(); errors.Is(err, red.ErrClosed) {
+		t.Fatal("expected the replaced client open until its reads are done")
+	}
+	done()
+	deadline := time.Now().Add(time.Second)
+	for !errors.Is(old.Ping(context.Background()).Err(), red.ErrClosed) {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the replaced client closed after its reads")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestConnCloseHook(t *testing.T) {
+	var closed int
+	dial := connCloseHook{onClose: func() {
+		closed++
+	}}.DialHook(func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, _ := net.Pipe()
+		return conn, nil
+	})
+
+	conn, err := dial(context.Background(), "tcp", "127.0.0.1:6379")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.Close()
+	conn.Close()
+	if closed != 1 {
+		t.Fatalf("expected one notification, got %d", closed)
+	}
+}
+
+func TestTrackingCluster(t *testing.T) {
+	_, err := NewRedis("127.0.0.1:7000", &Option{Type: TypeCluster, TrackingSize: 10})
+	if err != ErrTrackingCluster {
+		t.Fatalf("expected ErrTrackingCluster, got %v", err)
+	}
+}
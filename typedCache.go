@@ -0,0 +1,538 @@
+package rredis
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"sync"
+	"time"
+
+	red "github.com/redis/go-redis/v9"
+)
+
+const (
+	// values of Cache are prefixed with a flag byte, to tell them from tombstones.
+	flagValue    byte = 'v'
+	flagNotFound byte = 'n'
+	// flagStale values are followed by their logical expiry and load duration in milliseconds,
+	// see WithStaleWhileRevalidate and WithEarlyRefresh.
+	flagStale  byte = 's'
+	staleStamp      = 16
+)
+
+// ErrInvalidCacheValue is an error that indicates a cached value not written by Cache.
+var ErrInvalidCacheValue = errors.New("invalid cache value")
+
+// Cache is a typed cache built on Redis, values are encoded with its Codec.
+//
+//	users := rredis.NewCache[User](client, rredis.WithCacheExpire(time.Hour, 2*time.Hour))
+//	user, err := users.GetOrLoad(ctx, "user:1", func(ctx context.Context) (User, error) {
+//		return queryUser(ctx, 1)
+//	})
+type Cache[T any] struct {
+	rds  *Redis
+	opts *cacheOptions
+	// local is the in-memory cache in front of redis, see WithLocalCache.
+	local *nearCache[T]
+	// flightSuffix keeps the loads of caches of other types on the same key apart,
+	// as the single flight is shared by the caches of rds.
+	flightSuffix string
+	// refreshing holds the keys being refreshed in the background.
+	refreshing sync.Map
+}
+
+// cacheEntry is a decoded value with its logical expiry and load duration,
+// both zero if the value isn't stored with them.
+type cacheEntry[T any] struct {
+	val      T
+	expireAt time.Time
+	delta    time.Duration
+}
+
+// NewCache returns a Cache of values of type T stored in rds.
+// With WithLocalCache, the Cache subscribes to invalidations until it's closed.
+func NewCache[T any](rds *Redis, opts ...CacheOption) *Cache[T] {
+	c := &Cache[T]{
+		rds:          rds,
+		opts:         newCacheOptions(opts...),
+		flightSuffix: "#" + reflect.TypeOf((*T)(nil)).Elem().String(),
+	}
+	if c.opts.localSize > 0 {
+		c.local = newNearCache[T](rds, c.opts)
+	}
+
+	return c
+}
+
+// Close stops the invalidation subscription of the local cache.
+func (c *Cache[T]) Close() error {
+	if c.local != nil {
+		return c.local.Close()
+	}
+
+	return nil
+}
+
+// Get returns the cached value of key, it returns Nil if key isn't cached,
+// and ErrNotFound if key is cached as not found.
+// With WithStaleWhileRevalidate, expired values are returned during the grace window.
+func (c *Cache[T]) Get(ctx context.Context, key string) (val T, err error) {
+	entry, err := c.get(ctx, key)
+	return entry.val, err
+}
+
+func (c *Cache[T]) get(ctx context.Context, key string) (entry cacheEntry[T], err error) {
+	var seq uint64
+	if c.local != nil {
+		if val, ok := c.local.Get(c.rds.key(key)); ok {
+			entry.val = val
+			return entry, nil
+		}
+		seq = c.local.current()
+	}
+
+	data, err := c.rds.client.Get(ctx, c.rds.key(key)).Bytes()
+	if err != nil {
+		return entry, err
+	}
+
+	if entry, err = c.decode(data); err == nil && c.local != nil {
+		c.local.store(seq, c.rds.key(key), entry.val)
+	}
+
+	return
+}
+
+// Set caches val with key, local copies of key in other instances are invalidated.
+func (c *Cache[T]) Set(ctx context.Context, key string, val T) error {
+	if err := c.set(ctx, key, val, 0); err != nil {
+		return err
+	}
+
+	if c.local != nil {
+		return c.local.Invalidate(ctx, c.rds.key(key))
+	}
+
+	return nil
+}
+
+// Delete deletes the cached values of keys, local copies in all instances are invalidated.
+func (c *Cache[T]) Delete(ctx context.Context, keys ...string) error {
+	if _, err := c.rds.DelCtx(ctx, keys...); err != nil {
+		return err
+	}
+
+	if c.local != nil {
+		prefixed := c.rds.keys(keys)
+		c.local.drop(prefixed...)
+		return c.local.Invalidate(ctx, prefixed...)
+	}
+
+	return nil
+}
+
+// set caches val that took delta to load.
+func (c *Cache[T]) set(ctx context.Context, key string, val T, delta time.Duration) error {
+	data, expire, err := c.encode(val, delta)
+	if err != nil {
+		return err
+	}
+
+	var seq uint64
+	if c.local != nil {
+		seq = c.local.current()
+	}
+	if err = c.rds.client.Set(ctx, c.rds.key(key), data, expire).Err(); err != nil {
+		return err
+	}
+
+	if c.local != nil {
+		c.local.store(seq, c.rds.key(key), val)
+	}
+
+	return nil
+}
+
+// GetOrLoad returns the cached value of key, or loads it with loader and caches it.
+// Concurrent misses of the same key in one process share one loader call.
+// If loader returns ErrNotFound, it's cached as a tombstone, see WithNotFoundExpire.
+// With WithEarlyRefresh or WithStaleWhileRevalidate, values about to expire or stale are returned
+// while loader refreshes them in the background.
+func (c *Cache[T]) GetOrLoad(ctx context.Context, key string, loader func(ctx context.Context) (T, error)) (
+	T, error) {
+	entry, err := c.get(ctx, key)
+	if err == nil && c.shouldRefresh(entry) {
+		if time.Now().After(entry.expireAt) {
+			c.rds.stats.stale(c.rds.prefix)
+		}
+		c.refresh(key, loader)
+	}
+	val := entry.val
+	if err == nil || err == ErrNotFound {
+		c.rds.stats.hit(c.rds.prefix, 1)
+		return val, err
+	}
+	c.rds.stats.miss(c.rds.prefix, 1)
+
+	load := func() (interface{}, error) {
+		return c.load(ctx, key, loader)
+	}
+
+	v, err := c.rds.flight.Do(c.rds.key(key)+c.flightSuffix, func() (interface{}, error) {
+		if c.opts.lockExpire > 0 {
+			return c.rds.rebuildWithLock(ctx, key, c.opts, func() (interface{}, error) {
+				entry, err := c.get(ctx, key)
+				return entry.val, err
+			}, load)
+		}
+
+		return load()
+	})
+	if err != nil {
+		return val, err
+	}
+
+	val, ok := v.(T)
+	if !ok && v != nil {
+		return val, fmt.Errorf("%w: %T loaded for %s", ErrInvalidCacheValue, v, key)
+	}
+
+	return val, nil
+}
+
+// load loads key with loader and caches it, or caches a tombstone if it's not found.
+func (c *Cache[T]) load(ctx context.Context, key string, loader func(ctx context.Context) (T, error)) (T, error) {
+	start := time.Now()
+	val, err := loader(ctx)
+	c.rds.stats.load(c.rds.prefix, time.Since(start), err)
+	if errors.Is(err, ErrNotFound) && c.opts.notFoundExpire > 0 {
+		if err := c.setNotFound(ctx, key); err != nil {
+			return val, err
+		}
+		return val, ErrNotFound
+	}
+	if err != nil {
+		return val, err
+	}
+
+	return val, c.set(ctx, key, val, time.Since(start))
+}
+
+// shouldRefresh tells if entry is stale, or should be refreshed early with XFetch,
+// that is if now - delta * beta * ln(rand) passes its expiry.
+func (c *Cache[T]) shouldRefresh(entry cacheEntry[T]) bool {
+	if entry.expireAt.IsZero() {
+		return false
+	}
+
+	now := time.Now()
+	if !now.Before(entry.expireAt) {
+		return true
+	}
+	if c.opts.beta <= 0 {
+		return false
+	}
+
+	gap := -float64(entry.delta) * c.opts.beta * math.Log(1-rand.Float64())
+	return !now.Add(time.Duration(gap)).Before(entry.expireAt)
+}
+
+// refresh reloads key in the background, unless it's already being refreshed,
+// by this process or, with WithRebuildLock, by another one.
+// The cached value is left as is if loader fails.
+func (c *Cache[T]) refresh(key string, loader func(ctx context.Context) (T, error)) {
+	if _, loaded := c.refreshing.LoadOrStore(key, struct{}{}); loaded {
+		return
+	}
+
+	go func() {
+		defer c.refreshing.Delete(key)
+
+		ctx := c.rds.ctx
+		if c.opts.lockExpire > 0 {
+			lockKey := key + rebuildLockSuffix
+			token := randomToken()
+			ok, err := c.rds.client.SetNX(ctx, c.rds.key(lockKey), token, c.opts.lockExpire).Result()
+			if err != nil || !ok {
+				return
+			}
+			defer c.rds.EvalCtx(ctx, releaseScript, []string{lockKey}, token)
+		}
+
+		if _, err := c.load(ctx, key, loader); err == nil && c.local != nil {
+			c.local.Invalidate(ctx, c.rds.key(key))
+		}
+	}()
+}
+
+// refreshMany reloads keys in the background with one loader call, except the ones already
+// being refreshed, by this process or, with WithRebuildLock, by another one.
+// The cached values are left as is if loader fails.
+func (c *Cache[T]) refreshMany(keys []string,
+	loader func(ctx context.Context, missing []string) (map[string]T, error)) {
+	var pending []string
+	for _, key := range keys {
+		if _, loaded := c.refreshing.LoadOrStore(key, struct{}{}); !loaded {
+			pending = append(pending, key)
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	go func() {
+		defer func() {
+			for _, key := range pending {
+				c.refreshing.Delete(key)
+			}
+		}()
+
+		ctx := c.rds.ctx
+		if c.opts.lockExpire > 0 {
+			token := randomToken()
+			locked, err := c.lockMany(ctx, pending, token)
+			if err != nil || len(locked) == 0 {
+				return
+			}
+			defer c.rds.client.Pipelined(ctx, func(pipe red.Pipeliner) error {
+				for _, key := range locked {
+					pipe.Eval(ctx, releaseScript, []string{c.rds.key(key + rebuildLockSuffix)}, token)
+				}
+				return nil
+			})
+			pending = locked
+		}
+
+		start := time.Now()
+		loaded, err := loader(ctx, pending)
+		delta := time.Since(start)
+		c.rds.stats.load(c.rds.prefix, delta, err)
+		if err != nil {
+			return
+		}
+
+		if err = c.setMany(ctx, pending, loaded, delta); err == nil && c.local != nil {
+			c.local.Invalidate(ctx, c.rds.keys(pending)...)
+		}
+	}()
+}
+
+// lockMany takes the rebuild locks of keys with token in one pipeline, it returns the keys locked.
+func (c *Cache[T]) lockMany(ctx context.Context, keys []string, token string) ([]string, error) {
+	cmds, err := c.rds.client.Pipelined(ctx, func(pipe red.Pipeliner) error {
+		for _, key := range keys {
+			pipe.SetNX(ctx, c.rds.key(key+rebuildLockSuffix), token, c.opts.lockExpire)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var locked []string
+	for i, cmd := range cmds {
+		if cmd.(*red.BoolCmd).Val() {
+			locked = append(locked, keys[i])
+		}
+	}
+
+	return locked, nil
+}
+
+// MGetOrLoad returns the cached values of keys, the missing ones are loaded
+// with one loader call and cached in one pipeline. In cluster mode, keys are read with one MGET per slot.
+// Keys that are neither cached nor loaded are absent from the result,
+// the ones not loaded are cached as not found, see WithNotFoundExpire.
+// Values that can't be decoded are loaded again.
+// With WithEarlyRefresh or WithStaleWhileRevalidate, values about to expire or stale are returned
+// while one loader call refreshes them in the background.
+func (c *Cache[T]) MGetOrLoad(ctx context.Context, keys []string,
+	loader func(ctx context.Context, missing []string) (map[string]T, error)) (map[string]T, error) {
+	result := make(map[string]T, len(keys))
+	if len(keys) == 0 {
+		return result, nil
+	}
+
+	remote := keys
+	var seq uint64
+	if c.local != nil {
+		remote = nil
+		for _, key := range keys {
+			if val, ok := c.local.Get(c.rds.key(key)); ok {
+				result[key] = val
+			} else {
+				remote = append(remote, key)
+			}
+		}
+		if len(remote) == 0 {
+			c.rds.stats.hit(c.rds.prefix, len(keys))
+			return result, nil
+		}
+		seq = c.local.current()
+	}
+
+	vals, err := c.rds.mget(ctx, c.rds.keys(remote))
+	if err != nil {
+		return nil, err
+	}
+
+	var missing, stale []string
+	for i, v := range vals {
+		data, ok := v.(string)
+		if !ok {
+			missing = append(missing, remote[i])
+			continue
+		}
+
+		entry, err := c.decode([]byte(data))
+		if err == ErrNotFound {
+			continue
+		}
+		// values not written by this cache are loaded again, like GetOrLoad does.
+		if err != nil {
+			missing = append(missing, remote[i])
+			continue
+		}
+		result[remote[i]] = entry.val
+		if c.local != nil {
+			c.local.store(seq, c.rds.key(remote[i]), entry.val)
+		}
+		if c.shouldRefresh(entry) {
+			if time.Now().After(entry.expireAt) {
+				c.rds.stats.stale(c.rds.prefix)
+			}
+			stale = append(stale, remote[i])
+		}
+	}
+	if len(stale) > 0 {
+		c.refreshMany(stale, loader)
+	}
+	c.rds.stats.hit(c.rds.prefix, len(keys)-len(missing))
+	c.rds.stats.miss(c.rds.prefix, len(missing))
+	if len(missing) == 0 {
+		return result, nil
+	}
+
+	start := time.Now()
+	loaded, err := loader(ctx, missing)
+	delta := time.Since(start)
+	c.rds.stats.load(c.rds.prefix, delta, err)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, val := range loaded {
+		result[key] = val
+	}
+
+	return result, c.setMany(ctx, missing, loaded, delta)
+}
+
+// setMany caches the values loaded for keys that took delta to load in one pipeline,
+// the keys not loaded are cached as not found.
+func (c *Cache[T]) setMany(ctx context.Context, keys []string, loaded map[string]T, delta time.Duration) error {
+	var seq uint64
+	if c.local != nil {
+		seq = c.local.current()
+	}
+	_, err := c.rds.client.Pipelined(ctx, func(pipe red.Pipeliner) error {
+		for key, val := range loaded {
+			data, expire, err := c.encode(val, delta)
+			if err != nil {
+				return err
+			}
+
+			pipe.Set(ctx, c.rds.key(key), data, expire)
+		}
+
+		if c.opts.notFoundExpire > 0 {
+			for _, key := range keys {
+				if _, ok := loaded[key]; !ok {
+					pipe.Set(ctx, c.rds.key(key), c.tombstone(), c.opts.notFoundExpire)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil || c.local == nil {
+		return err
+	}
+
+	for key, val := range loaded {
+		c.local.store(seq, c.rds.key(key), val)
+	}
+
+	return nil
+}
+
+func (c *Cache[T]) setNotFound(ctx context.Context, key string) error {
+	return c.rds.client.Set(ctx, c.rds.key(key), c.tombstone(), c.opts.notFoundExpire).Err()
+}
+
+// tombstone returns the stored form of keys not found.
+func (c *Cache[T]) tombstone() []byte {
+	if c.opts.raw {
+		return []byte(notFoundPlaceholder)
+	}
+
+	return []byte{flagNotFound}
+}
+
+// encode returns the stored form of val that took delta to load, and its redis expiration.
+// With WithEarlyRefresh or WithStaleWhileRevalidate, the logical expiry and delta are stored too,
+// and the redis expiration is extended by the grace window.
+func (c *Cache[T]) encode(val T, delta time.Duration) ([]byte, time.Duration, error) {
+	data, err := c.opts.codec.Marshal(val)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	expire := c.opts.expire()
+	if c.opts.raw {
+		return data, expire, nil
+	}
+	if c.opts.beta <= 0 && c.opts.grace <= 0 {
+		return append([]byte{flagValue}, data...), expire, nil
+	}
+
+	buf := make([]byte, 1+staleStamp, 1+staleStamp+len(data))
+	buf[0] = flagStale
+	binary.BigEndian.PutUint64(buf[1:], uint64(time.Now().Add(expire).UnixMilli()))
+	binary.BigEndian.PutUint64(buf[9:], uint64(delta.Milliseconds()))
+	return append(buf, data...), expire + c.opts.grace, nil
+}
+
+func (c *Cache[T]) decode(data []byte) (entry cacheEntry[T], err error) {
+	if c.opts.raw {
+		if string(data) == notFoundPlaceholder {
+			return entry, ErrNotFound
+		}
+		err = c.opts.codec.Unmarshal(data, &entry.val)
+		return
+	}
+	if len(data) == 0 {
+		return entry, ErrInvalidCacheValue
+	}
+
+	switch data[0] {
+	case flagValue:
+		err = c.opts.codec.Unmarshal(data[1:], &entry.val)
+	case flagStale:
+		if len(data) < 1+staleStamp {
+			return entry, ErrInvalidCacheValue
+		}
+		entry.expireAt = time.UnixMilli(int64(binary.BigEndian.Uint64(data[1:])))
+		entry.delta = time.Duration(binary.BigEndian.Uint64(data[9:])) * time.Millisecond
+		err = c.opts.codec.Unmarshal(data[1+staleStamp:], &entry.val)
+	case flagNotFound:
+		err = ErrNotFound
+	default:
+		err = ErrInvalidCacheValue
+	}
+
+	return
+}
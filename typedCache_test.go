@@ -0,0 +1,154 @@
+package rredis
+
+import (
+	"context"
+	"encoding/binary"
+	"testing"
+	"time"
+)
+
+func TestCacheStaleEntry(t *testing.T) {
+	c := NewCache[string](nil, WithCacheExpire(time.Minute, time.Minute), WithStaleWhileRevalidate(time.Hour))
+
+	data, expire, err := c.encode("tom", 20*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expire != time.Minute+time.Hour {
+		t.Fatalf("expected the expiration to include the grace window, got %s", expire)
+	}
+
+	entry, err := c.decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.val != "tom" || entry.delta != 20*time.Millisecond {
+		t.Fatalf("unexpected entry: %+v", entry)
+	}
+	if d := time.Until(entry.expireAt); d <= 0 || d > time.Minute {
+		t.Fatalf("unexpected logical expiry in %s", d)
+	}
+	if c.shouldRefresh(entry) {
+		t.Fatal("expected a fresh entry not to be refreshed")
+	}
+
+	entry.expireAt = time.Now().Add(-time.Second)
+	if !c.shouldRefresh(entry) {
+		t.Fatal("expected a stale entry to be refreshed")
+	}
+}
+
+func TestCacheEarlyRefresh(t *testing.T) {
+	c := NewCache[string](nil, WithEarlyRefresh(1))
+	entry := cacheEntry[string]{
+		expireAt: time.Now().Add(time.Millisecond),
+		delta:    time.Hour,
+	}
+
+	// a load taking much longer than the time left is almost always refreshed early.
+	var refreshed int
+	for i := 0; i < 100; i++ {
+		if c.shouldRefresh(entry) {
+			refreshed++
+		}
+	}
+	if refreshed < 90 {
+		t.Fatalf("expected most lookups to refresh early, got %d", refreshed)
+	}
+
+	entry.expireAt = time.Now().Add(time.Hour)
+	entry.delta = time.Millisecond
+	if c.shouldRefresh(entry) {
+		t.Fatal("expected no early refresh far from the expiry")
+	}
+}
+
+func TestCacheMGetOrLoadStale(t *testing.T) {
+	rds, h := newMemoryRedis()
+	c := NewCache[string](rds, WithCacheExpire(time.Minute, time.Minute), WithStaleWhileRevalidate(time.Hour))
+
+	data, _, err := c.encode("old", time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// moves the logical expiry a second in the past.
+	binary.BigEndian.PutUint64(data[1:], uint64(time.Now().Add(-time.Second).UnixMilli()))
+	h.data["user:1"] = string(data)
+	fresh, _, err := c.encode("bob", time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.data["user:2"] = string(fresh)
+
+	refreshed := make(chan []string, 1)
+	loader := func(ctx context.Context, missing []string) (map[string]string, error) {
+		refreshed <- missing
+		return map[string]string{"user:1": "new"}, nil
+	}
+
+	vals, err := c.MGetOrLoad(context.Background(), []string{"user:1", "user:2"}, loader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if vals["user:1"] != "old" || vals["user:2"] != "bob" {
+		t.Fatalf("expected the stale value served, got %v", vals)
+	}
+
+	select {
+	case keys := <-refreshed:
+		if len(keys) != 1 || keys[0] != "user:1" {
+			t.Fatalf("expected only the stale key refreshed, got %v", keys)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("expected the stale key refreshed in the background")
+	}
+	if stats := rds.Stats()[""]; stats.StaleServes != 1 {
+		t.Fatalf("expected one stale serve, got %+v", stats)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		val, err := c.Get(context.Background(), "user:1")
+		if err == nil && val == "new" {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("expected the refreshed value cached, got %q, %v", val, err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCacheGetOrLoadTypes(t *testing.T) {
+	rds, _ := newMemoryRedis()
+	names := NewCache[string](rds)
+	counts := NewCache[int](rds)
+
+	// counts loads the key while names is loading it, each cache calls its own loader.
+	started, release := make(chan struct{}), make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		_, err := names.GetOrLoad(context.Background(), "user:1", func(ctx context.Context) (string, error) {
+			close(started)
+			<-release
+			return "ann", nil
+		})
+		done <- err
+	}()
+	<-started
+	timer := time.AfterFunc(time.Second, func() { close(release) })
+	defer timer.Stop()
+
+	val, err := counts.GetOrLoad(context.Background(), "user:1", func(ctx context.Context) (int, error) {
+		return 7, nil
+	})
+	if err != nil || val != 7 {
+		t.Fatalf("expected the value of the int loader, got %d, %v", val, err)
+	}
+	if timer.Stop() {
+		close(release)
+	}
+	if err = <-done; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,6 +1,8 @@
 package rredis
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	red "github.com/redis/go-redis/v9"
 	"reflect"
@@ -145,3 +147,13 @@ func toFloatPairs(vals []red.Z) []FloatPair {
 
 	return pairs
 }
+
+// randomToken returns a random hex string, used to identify lock owners.
+func randomToken() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+
+	return hex.EncodeToString(b)
+}